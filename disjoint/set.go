@@ -0,0 +1,65 @@
+// Package disjoint implements a disjoint-set (union-find) structure.
+package disjoint
+
+// Set partitions the integers [0, n) into disjoint subsets. Unlike cycle.Set,
+// it can cheaply answer whether two members belong to the same subset, but it
+// cannot list the members of a subset.
+type Set struct {
+	parent []int
+	rank   []int
+	count  int
+}
+
+// New returns a Set of n singleton subsets.
+func New(n int) *Set {
+	s := &Set{
+		parent: make([]int, n),
+		rank:   make([]int, n),
+		count:  n,
+	}
+	for i := range s.parent {
+		s.parent[i] = i
+	}
+	return s
+}
+
+// Find returns the representative member of x's subset. Every node on the path
+// to the root is re-pointed directly at the root (path compression).
+func (s *Set) Find(x int) int {
+	root := x
+	for s.parent[root] != root {
+		root = s.parent[root]
+	}
+	for s.parent[x] != root {
+		s.parent[x], x = root, s.parent[x]
+	}
+	return root
+}
+
+// Union merges the subsets containing x and y. It returns false if they were
+// already the same subset. The shallower tree is attached under the deeper one
+// (union by rank).
+func (s *Set) Union(x, y int) bool {
+	x, y = s.Find(x), s.Find(y)
+	if x == y {
+		return false
+	}
+	if s.rank[x] < s.rank[y] {
+		x, y = y, x
+	}
+	s.parent[y] = x
+	if s.rank[x] == s.rank[y] {
+		s.rank[x]++
+	}
+	s.count--
+	return true
+}
+
+// Same reports whether x and y are in the same subset.
+func (s *Set) Same(x, y int) bool { return s.Find(x) == s.Find(y) }
+
+// Len returns the number of members.
+func (s *Set) Len() int { return len(s.parent) }
+
+// Count returns the number of distinct subsets.
+func (s *Set) Count() int { return s.count }
@@ -0,0 +1,42 @@
+package disjoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_Union(t *testing.T) {
+	require := require.New(t)
+	s := New(6)
+	require.Equal(6, s.Count())
+	require.False(s.Same(0, 1))
+
+	require.True(s.Union(0, 1))
+	require.True(s.Union(2, 3))
+	require.True(s.Same(0, 1))
+	require.True(s.Same(3, 2))
+	require.False(s.Same(1, 2))
+	require.Equal(4, s.Count())
+
+	require.True(s.Union(1, 3))
+	require.True(s.Same(0, 2))
+	require.False(s.Union(0, 3), "already merged")
+	require.Equal(3, s.Count())
+	require.False(s.Same(4, 5))
+}
+
+func TestSet_Find(t *testing.T) {
+	require := require.New(t)
+	s := New(100)
+	// Build a long chain, then make sure everything compresses to one root.
+	for i := 1; i < s.Len(); i++ {
+		s.Union(i-1, i)
+	}
+	root := s.Find(0)
+	for i := 0; i < s.Len(); i++ {
+		require.Equal(root, s.Find(i))
+		require.Equal(root, s.parent[i], "path not compressed for %d", i)
+	}
+	require.Equal(1, s.Count())
+}
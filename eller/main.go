@@ -41,6 +41,8 @@ The Row assembly is a singly-linked list that cycles back to the control node.
      └────► Cell ├─────► Cell ├─...─► Cell ├─┘
           └──────┘     └──────┘     └──────┘
 
+Every cell is also connected to the control node's "finalRow" broadcast.
+
 TODO: Describe set membership wiring
 */
 
@@ -48,9 +50,12 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log"
 	"math/rand"
+	"os"
 	"time"
 
+	"github.com/misterikkit/automata/gen"
 	"github.com/misterikkit/automata/horizon"
 	"github.com/misterikkit/automata/wall"
 )
@@ -58,11 +63,17 @@ import (
 func main() {
 	h := flag.Int("h", 10, "height")
 	w := flag.Int("w", 10, "width")
+	merge := flag.Float64("merge", 0.5, "probability of merging adjacent groups")
+	vertical := flag.String("vertical", "uniform", "how many cells of each group open south. One of (one, uniform, cell:P)")
+	bands := flag.String("bands", "", "optional per-row overrides as ROW,MERGE,VERTICAL;..., e.g. 0,0.9,one;20,0.3,cell:0.5")
+	dot := flag.String("dot", "", "filename to write the object graph in Graphviz DOT format")
 	flag.Parse()
-	rand.Seed(time.Now().Unix())
+	rng := rand.New(rand.NewSource(time.Now().Unix()))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+	params, err := gen.ParseEllerOptions(*merge, *vertical, *bands)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	maze := wall.NewMaze(*h, *w)
 	loop := horizon.NewEventLoop()
@@ -70,18 +81,23 @@ func main() {
 	cells := make([]horizon.Object, *w)
 	for i := range cells {
 		last := i == len(cells)-1
-		cells[i] = horizon.NewObject(fmt.Sprintf("cell-%02d", i), Cell(last), loop)
+		cells[i] = horizon.NewObject(fmt.Sprintf("cell-%02d", i), Cell(last, rng), loop)
+		cells[i].Handles(CellEvents)
+		cells[i].Requires(CellWires...)
 	}
 	// Workaround to simulate the moving and trigger detecting of wall objects
 	row := 0
 	ctrl := horizon.NewObject("controller", Controller(*h, func() {
 		row++
-		updateTriggers(cells, maze, row)
-	}, cancel), loop)
-	updateTriggers(cells, maze, row)
+		updateTriggers(cells, maze, row, gen.ParamsFor(params, row))
+	}), loop)
+	ctrl.Handles(ControllerEvents)
+	ctrl.Requires(ControllerWires...)
+	updateTriggers(cells, maze, row, gen.ParamsFor(params, row))
 
 	ctrl.Wire(horizon.Wiring{"head": cells[0]})
 	for i := range cells {
+		cells[i].Connect(ctrl, "finalRow")
 		j := i + 1
 		if j < len(cells) {
 			cells[i].Wire(horizon.Wiring{"nextCell": cells[j]})
@@ -90,12 +106,46 @@ func main() {
 		}
 	}
 
-	loop.Run(ctx)
+	if errs := loop.Validate(); len(errs) > 0 {
+		for _, err := range errs {
+			log.Print(err)
+		}
+		os.Exit(1)
+	}
+	if len(*dot) > 0 {
+		if err := writeDOT(*dot, loop); err != nil {
+			log.Printf("DOT write fail: %v", err)
+		}
+	}
+	summary := loop.Run(context.Background())
+	log.Printf("Event loop stopped: %v", summary)
+	for _, err := range summary.Errors {
+		if de, ok := err.(*horizon.DispatchError); ok {
+			log.Print(de.Details())
+			continue
+		}
+		log.Print(err)
+	}
 	fmt.Println(maze)
 }
 
-func updateTriggers(cells []horizon.Object, maze *wall.Maze, row int) {
+func writeDOT(filename string, loop horizon.EventLoop) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := horizon.WriteDOT(f, loop, horizon.GroupByPrefix("-")); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func updateTriggers(cells []horizon.Object, maze *wall.Maze, row int, params gen.EllerParams) {
 	for i := range cells {
+		// Not a trigger, but it changes at the same time. Stands in for row-specific
+		// props on the cell objects.
+		cells[i].Send(cells[i], "params", params)
 		col := i
 		cells[i].Send(cells[i], "triggerEast", func() {
 			maze.Open(row, col, wall.East)
@@ -1,12 +1,25 @@
 package main
 
 import (
+	"log"
 	"math/rand"
 
+	"github.com/misterikkit/automata/gen"
 	"github.com/misterikkit/automata/horizon"
 )
 
-func Controller(rows int, moveToNext func(), done func()) horizon.Script {
+// ControllerEvents are the events handled by Controller.
+var ControllerEvents = horizon.Schema{
+	"computeEWBegin": nil,
+	"computeEW":      nil,
+	"computeNS":      nil,
+	"finished":       nil,
+}
+
+// ControllerWires are the wires required by Controller.
+var ControllerWires = []string{"head"}
+
+func Controller(rows int, moveToNext func()) horizon.Script {
 	return func(self horizon.Object, e horizon.Event) {
 		head := self.Wires()["head"]
 		switch e.Name {
@@ -25,27 +38,50 @@ func Controller(rows int, moveToNext func(), done func()) horizon.Script {
 				self.Send(head, "computeEW", nil)
 			}
 			if rows == 1 {
-				self.Send(head, "finalRow", nil)
+				// Every cell hears this before the row starts.
+				self.Broadcast("finalRow", nil)
+				self.Send(head, "computeEW", nil)
 			}
-		case "finalRow":
-			self.Send(head, "computeEW", nil)
 		case "finished":
-			done()
+			// Nothing left to send, so the event loop stops by itself.
 		}
 	}
 }
 
-func Cell(last bool) horizon.Script {
+// CellEvents are the events handled by Cell.
+var CellEvents = horizon.Schema{
+	"triggerEast":    horizon.ArgType(func() {}),
+	"triggerSouth":   horizon.ArgType(func() {}),
+	"params":         horizon.ArgType(gen.EllerParams{}),
+	"finalRow":       nil,
+	"computeEW":      nil,
+	"groupSearch":    horizon.ObjectArg,
+	"getGroupNext":   nil,
+	"setGroupNext":   horizon.ObjectArg,
+	"setGroupPrev":   horizon.ObjectArg,
+	"swapGroupPrev":  horizon.ObjectArg,
+	"getGroupPrev":   nil,
+	"swapComplete":   nil,
+	"groupFound":     horizon.ObjectArg,
+	"computeNS":      nil,
+	"groupCount":     horizon.ArgType(0),
+	"openSouthMaybe": horizon.ArgType(vector{}),
+}
+
+// CellWires are the wires required by Cell.
+var CellWires = []string{"nextCell"}
+
+func Cell(last bool, rng *rand.Rand) horizon.Script {
 	var (
 		lastCell = last
+		// tuning for the current row
+		params = gen.DefaultEllerParams
 		// open funcs each take the place of a trigger + wall object
 		openEast  func()
 		openSouth func()
 		// doubly linked list (cycle) of group members
 		groupNext horizon.Object
 		groupPrev horizon.Object
-		// temporary variable
-		swapBuddy horizon.Object
 
 		groupHead bool // true if we should terminate a group walk here. (e.g. group search or group count)
 		cellDone  bool // tracks when both east and south decisions have been made for this cell
@@ -60,10 +96,11 @@ func Cell(last bool) horizon.Script {
 			openEast = e.Arg.(func())
 		case "triggerSouth":
 			openSouth = e.Arg.(func())
+		case "params":
+			params = e.Arg.(gen.EllerParams)
 
 		case "finalRow":
 			finalRow = true
-			self.Send(nextCell, "finalRow", nil)
 
 		case "computeEW":
 			cellDone = false
@@ -98,12 +135,22 @@ func Cell(last bool) horizon.Script {
 				// nextCell is not in our group!
 				// randomly decide to merge
 				// In final row, always merge
-				if finalRow || p(0.5) {
+				if finalRow || p(rng, params.Merge) {
 					// invoke openEast
 					openEast()
 					// Swap the groupNext of self and nextCell, and the groupPrev of
 					// self.groupNext and nextCell.groupNext.
-					self.Send(nextCell, "getGroupNext", self)
+					self.Call(nextCell, "getGroupNext", nil, func(reply interface{}, err error) {
+						if err != nil {
+							log.Print(err)
+							return
+						}
+						obj := reply.(horizon.Object)
+						// This is fire-and-forget, but should execute before the swapGroupPrev maneuver.
+						self.Send(nextCell, "setGroupNext", groupNext)
+						self.Send(groupNext, "swapGroupPrev", obj)
+						groupNext = obj
+					})
 				} else {
 					// TODO: else is not supported in Horizon
 					// send "computeEW" to nextCell
@@ -112,15 +159,7 @@ func Cell(last bool) horizon.Script {
 			}
 
 		case "getGroupNext":
-			obj := e.Arg.(horizon.Object)
-			self.Send(obj, "getGroupNextResp", groupNext)
-
-		case "getGroupNextResp":
-			obj := e.Arg.(horizon.Object)
-			// This is fire-and-forget, but should execute before the swapGroupPrev maneuver.
-			self.Send(nextCell, "setGroupNext", groupNext)
-			self.Send(groupNext, "swapGroupPrev", obj)
-			groupNext = obj
+			self.Reply(e, groupNext)
 
 		case "setGroupNext":
 			obj := e.Arg.(horizon.Object)
@@ -131,22 +170,23 @@ func Cell(last bool) horizon.Script {
 			groupPrev = obj
 
 		case "swapGroupPrev":
-			obj := e.Arg.(horizon.Object)
-			// Trade groupPrev values with obj
-			swapBuddy = obj
-			self.Send(swapBuddy, "getGroupPrev", self)
+			// Trade groupPrev values with buddy
+			buddy := e.Arg.(horizon.Object)
+			self.Call(buddy, "getGroupPrev", nil, func(reply interface{}, err error) {
+				if err != nil {
+					log.Print(err)
+					return
+				}
+				obj := reply.(horizon.Object)
+				// One of these groupPrev values is the one which initiated the
+				// swapGroupPrev. Signal it that swap is complete.
+				self.Send(buddy, "setGroupPrev", groupPrev)
+				self.Send(groupPrev, "swapComplete", nil)
+				groupPrev = obj
+			})
 
 		case "getGroupPrev":
-			obj := e.Arg.(horizon.Object)
-			self.Send(obj, "getGroupPrevResp", groupPrev)
-
-		case "getGroupPrevResp":
-			obj := e.Arg.(horizon.Object)
-			// One of these groupPrev values is the one which initiated the
-			// swapGroupPrev. Signal it that swap is complete.
-			self.Send(swapBuddy, "setGroupPrev", groupPrev)
-			self.Send(groupPrev, "swapComplete", nil)
-			groupPrev = obj
+			self.Reply(e, groupPrev)
 
 		case "swapComplete":
 			self.Send(nextCell, "computeEW", nil)
@@ -176,7 +216,7 @@ func Cell(last bool) horizon.Script {
 				self.Send(groupNext, "groupCount", count+1)
 			}
 			if groupHead {
-				numToOpen := 1 + rand.Intn(count) // TODO: inline this ):
+				numToOpen := params.Vertical(rng, count) // TODO: inline this ):
 				self.Send(groupNext, "openSouthMaybe", vector{x: float32(numToOpen), y: float32(count)})
 			}
 
@@ -185,7 +225,7 @@ func Cell(last bool) horizon.Script {
 			// In this group, open v.x of the remaining v.y cells. In otherwords, open
 			// this cell with probability v.x/v.y
 			cellDone = true
-			if p(v.x / v.y) {
+			if p(rng, float64(v.x/v.y)) {
 				openSouth()
 				if !groupHead {
 					self.Send(groupNext, "openSouthMaybe", vector{x: v.x - 1, y: v.y - 1})
@@ -216,6 +256,6 @@ func Cell(last bool) horizon.Script {
 type vector struct{ x, y, z float32 }
 
 // p returns true with probability equal to p.
-func p(p float32) bool {
-	return rand.Float32() < p
+func p(rng *rand.Rand, p float64) bool {
+	return rng.Float64() < p
 }
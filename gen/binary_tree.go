@@ -0,0 +1,101 @@
+package gen
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func init() {
+	Register("binary-tree", BinaryTree(wall.North|wall.East, 0.5))
+	Register("sidewinder", Sidewinder(wall.North|wall.East, 0.5))
+}
+
+// ParseCorner parses a bias corner such as "NE" or "sw" into the pair of
+// directions it is made of.
+func ParseCorner(s string) (wall.Direction, error) {
+	var corner wall.Direction
+	switch strings.ToUpper(s) {
+	case "NE":
+		corner = wall.North | wall.East
+	case "NW":
+		corner = wall.North | wall.West
+	case "SE":
+		corner = wall.South | wall.East
+	case "SW":
+		corner = wall.South | wall.West
+	default:
+		return 0, errors.Errorf("unknown corner %q. Must be one of NE, NW, SE, SW", s)
+	}
+	return corner, nil
+}
+
+// split separates a corner into its vertical and horizontal directions.
+func split(corner wall.Direction) (vertical, horizontal wall.Direction) {
+	return corner & (wall.North | wall.South), corner & (wall.East | wall.West)
+}
+
+// BinaryTree returns a generator using the binary tree algorithm. Each cell
+// opens one wall toward the given corner (e.g. wall.North|wall.East), choosing
+// the vertical side with probability p. The two borders at that corner end up
+// as unbroken corridors, and every path heads diagonally toward the corner.
+func BinaryTree(corner wall.Direction, p float64) Generator {
+	v, h := split(corner)
+	return func(m *wall.Maze, rng *rand.Rand) {
+		for r := 0; r < m.Rows(); r++ {
+			for c := 0; c < m.Cols(); c++ {
+				_, _, canV := m.Neighbor(r, c, v)
+				_, _, canH := m.Neighbor(r, c, h)
+				focus(m, Cell{r, c}, nil)
+				switch {
+				case canV && canH:
+					if rng.Float64() < p {
+						m.Open(r, c, v)
+					} else {
+						m.Open(r, c, h)
+					}
+				case canV:
+					m.Open(r, c, v)
+				case canH:
+					m.Open(r, c, h)
+				}
+			}
+		}
+	}
+}
+
+// Sidewinder returns a generator using the sidewinder algorithm. Each row is
+// carved into runs heading horizontally toward the given corner. A run is
+// closed with probability p at each cell, and then one random cell of the run
+// opens its vertical wall toward the corner. The row along the corner's
+// vertical border is a single corridor.
+func Sidewinder(corner wall.Direction, p float64) Generator {
+	v, h := split(corner)
+	return func(m *wall.Maze, rng *rand.Rand) {
+		for r := 0; r < m.Rows(); r++ {
+			_, _, canV := m.Neighbor(r, 0, v)
+			var run []int
+			for i := 0; i < m.Cols(); i++ {
+				// Runs extend toward h, so walk the row in that direction.
+				c := i
+				if h == wall.West {
+					c = m.Cols() - 1 - i
+				}
+				run = append(run, c)
+				focus(m, Cell{r, c}, nil)
+				_, _, canH := m.Neighbor(r, c, h)
+				if canH && (!canV || rng.Float64() >= p) {
+					m.Open(r, c, h)
+					continue
+				}
+				if canV {
+					m.Open(r, run[rng.Intn(len(run))], v)
+				}
+				run = run[:0]
+			}
+		}
+	}
+}
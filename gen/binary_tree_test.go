@@ -0,0 +1,48 @@
+package gen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestCorners(t *testing.T) {
+	for _, s := range []string{"ne", "NW", "Se", "sW"} {
+		corner, err := ParseCorner(s)
+		require.NoError(t, err)
+		v, h := split(corner)
+		for name, g := range map[string]Generator{
+			"binary-tree": BinaryTree(corner, 0.5),
+			"sidewinder":  Sidewinder(corner, 0.5),
+		} {
+			t.Run(name+"/"+s, func(t *testing.T) {
+				m := wall.NewMaze(8, 12)
+				g(m, rand.New(rand.NewSource(1)))
+				requirePerfect(t, m)
+				// The border row on the corner's vertical side is one long corridor.
+				row := 0
+				if v == wall.South {
+					row = m.Rows() - 1
+				}
+				for c := 0; c+1 < m.Cols(); c++ {
+					require.True(t, m.IsOpen(row, c, wall.East), "closed at %d,%d\n%v", row, c, m)
+				}
+				// Binary tree also makes a corridor along the horizontal side.
+				if name == "binary-tree" {
+					col := 0
+					if h == wall.East {
+						col = m.Cols() - 1
+					}
+					for r := 0; r+1 < m.Rows(); r++ {
+						require.True(t, m.IsOpen(r, col, wall.South), "closed at %d,%d\n%v", r, col, m)
+					}
+				}
+			})
+		}
+	}
+	_, err := ParseCorner("north")
+	require.Error(t, err)
+}
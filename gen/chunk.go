@@ -0,0 +1,97 @@
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+// World describes an endless maze made of equally sized chunks. Any chunk can
+// be generated on its own, in any order, and always comes out the same for the
+// same seed.
+//
+// Each chunk is filled by Generator and has exactly one door on each side,
+// shared with the neighboring chunk. With a perfect Generator, every chunk is
+// a perfect maze, and the only loops in the world go between chunks.
+type World struct {
+	Seed int64
+	// Size of each chunk
+	Rows, Cols int
+	// Generator fills in each chunk. Nil means Kruskal.
+	Generator Generator
+}
+
+// Chunk is one piece of a World.
+type Chunk struct {
+	*wall.Maze
+	// Doors holds the position of the opening on each side of the chunk, which
+	// the wall.Maze cannot show. North and South doors are columns; East and
+	// West doors are rows.
+	Doors map[wall.Direction]int
+}
+
+// Chunk generates the chunk at the given chunk coordinates, which may be
+// negative.
+func (w World) Chunk(row, col int) Chunk {
+	g := w.Generator
+	if g == nil {
+		g = Kruskal()
+	}
+	m := wall.NewMaze(w.Rows, w.Cols)
+	g(m, w.rng(row, col, 0))
+	return Chunk{
+		Maze: m,
+		Doors: map[wall.Direction]int{
+			// A chunk owns the doors on its south and east sides. The others belong
+			// to its neighbors.
+			wall.North: w.rng(row-1, col, wall.South).Intn(w.Cols),
+			wall.East:  w.rng(row, col, wall.East).Intn(w.Rows),
+			wall.South: w.rng(row, col, wall.South).Intn(w.Cols),
+			wall.West:  w.rng(row, col-1, wall.East).Intn(w.Rows),
+		},
+	}
+}
+
+// Region generates rows x cols chunks starting at the given chunk coordinates
+// and stitches them into one maze through their doors.
+func (w World) Region(row, col, rows, cols int) *wall.Maze {
+	m := wall.NewMaze(rows*w.Rows, cols*w.Cols)
+	for cr := 0; cr < rows; cr++ {
+		for cc := 0; cc < cols; cc++ {
+			chunk := w.Chunk(row+cr, col+cc)
+			top, left := cr*w.Rows, cc*w.Cols
+			for r := 0; r < w.Rows; r++ {
+				for c := 0; c < w.Cols; c++ {
+					for _, d := range []wall.Direction{wall.East, wall.South} {
+						if chunk.IsOpen(r, c, d) {
+							m.Open(top+r, left+c, d)
+						}
+					}
+				}
+			}
+			// Doors on the outside of the region are left closed by Open.
+			m.Open(top+chunk.Doors[wall.East], left+w.Cols-1, wall.East)
+			m.Open(top+w.Rows-1, left+chunk.Doors[wall.South], wall.South)
+		}
+	}
+	return m
+}
+
+// rng returns a random source for one chunk, or for one of the doors it owns.
+func (w World) rng(row, col int, d wall.Direction) *rand.Rand {
+	return rand.New(rand.NewSource(mix(w.Seed, int64(row), int64(col), int64(d))))
+}
+
+// mix hashes its inputs into a seed, so that nearby coordinates still get
+// unrelated random streams. Each step is the SplitMix64 finalizer.
+func mix(vs ...int64) int64 {
+	h := uint64(0x9e3779b97f4a7c15)
+	for _, v := range vs {
+		h ^= uint64(v)
+		h += 0x9e3779b97f4a7c15
+		h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+		h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+		h ^= h >> 31
+	}
+	return int64(h)
+}
@@ -0,0 +1,47 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestWorld_Chunk(t *testing.T) {
+	w := World{Seed: 42, Rows: 6, Cols: 9, Generator: Wilson()}
+	for _, pos := range [][2]int{{0, 0}, {-3, 7}, {1 << 20, -(1 << 20)}} {
+		row, col := pos[0], pos[1]
+		chunk := w.Chunk(row, col)
+		requirePerfect(t, chunk.Maze)
+		require.Equal(t, chunk.String(), w.Chunk(row, col).String(), "not deterministic")
+
+		require.Equal(t, chunk.Doors[wall.East], w.Chunk(row, col+1).Doors[wall.West])
+		require.Equal(t, chunk.Doors[wall.West], w.Chunk(row, col-1).Doors[wall.East])
+		require.Equal(t, chunk.Doors[wall.South], w.Chunk(row+1, col).Doors[wall.North])
+		require.Equal(t, chunk.Doors[wall.North], w.Chunk(row-1, col).Doors[wall.South])
+	}
+	other := World{Seed: 43, Rows: 6, Cols: 9, Generator: Wilson()}
+	require.NotEqual(t, w.Chunk(0, 0).String(), other.Chunk(0, 0).String())
+}
+
+func TestWorld_Region(t *testing.T) {
+	w := World{Seed: 1, Rows: 5, Cols: 5}
+	m := w.Region(-1, -1, 3, 4)
+	require.Equal(t, 15*20, countReachable(m, 0, 0), "not fully connected\n%v", m)
+	// Each internal chunk border adds exactly one opening to the chunks' trees.
+	borders := 2*4 + 3*3
+	require.Equal(t, 15*20-12+borders, countOpen(m))
+
+	// Regions agree where they overlap.
+	sub := w.Region(0, 0, 1, 1)
+	for r := 0; r < 5; r++ {
+		for c := 0; c < 5; c++ {
+			for _, d := range wall.Directions {
+				if _, _, ok := sub.Neighbor(r, c, d); ok {
+					require.Equal(t, m.IsOpen(r+5, c+5, d), sub.IsOpen(r, c, d))
+				}
+			}
+		}
+	}
+}
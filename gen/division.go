@@ -0,0 +1,104 @@
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func init() {
+	Register("division", Division(DivisionOptions{}))
+}
+
+// DivisionOptions configures a recursive division generator.
+type DivisionOptions struct {
+	// RoomSize leaves chambers undivided once they are no larger than RoomSize
+	// cells in either dimension. Values above 1 produce open rooms, and the
+	// result is no longer a perfect maze.
+	RoomSize int
+	// Bias is between -1 and 1. At zero, chambers are cut across their longer
+	// dimension (at random if square). Positive values favor horizontal cuts
+	// and negative values favor vertical cuts, with ±1 cutting only one way
+	// where possible.
+	Bias float64
+}
+
+// Division returns a generator using recursive division. Unlike the other
+// algorithms, this one adds walls: it opens the whole maze, then repeatedly
+// bisects each chamber with a wall that has a single gap. It is the only one
+// to produce long straight walls.
+func Division(opts DivisionOptions) Generator {
+	return func(m *wall.Maze, rng *rand.Rand) {
+		if m.Rows() == 0 || m.Cols() == 0 {
+			return
+		}
+		for r := 0; r < m.Rows(); r++ {
+			for c := 0; c < m.Cols(); c++ {
+				m.Open(r, c, wall.East)
+				m.Open(r, c, wall.South)
+			}
+		}
+		divide(m, rng, opts, Cell{0, 0}, m.Rows(), m.Cols())
+	}
+}
+
+// divide splits the chamber with the given top-left corner and size.
+func divide(m *wall.Maze, rng *rand.Rand, opts DivisionOptions, corner Cell, rows, cols int) {
+	if rows < 2 && cols < 2 {
+		return
+	}
+	if rows <= opts.RoomSize && cols <= opts.RoomSize {
+		return
+	}
+	focus(m, corner, nil)
+	if horizontal(rng, opts.Bias, rows, cols) {
+		// Wall along the south side of row y, with a gap at column x.
+		y := corner.Row + rng.Intn(rows-1)
+		x := corner.Col + rng.Intn(cols)
+		for c := corner.Col; c < corner.Col+cols; c++ {
+			if c != x {
+				m.Close(y, c, wall.South)
+			}
+		}
+		north := y - corner.Row + 1
+		divide(m, rng, opts, corner, north, cols)
+		divide(m, rng, opts, Cell{y + 1, corner.Col}, rows-north, cols)
+	} else {
+		// Wall along the east side of column x, with a gap at row y.
+		x := corner.Col + rng.Intn(cols-1)
+		y := corner.Row + rng.Intn(rows)
+		for r := corner.Row; r < corner.Row+rows; r++ {
+			if r != y {
+				m.Close(r, x, wall.East)
+			}
+		}
+		west := x - corner.Col + 1
+		divide(m, rng, opts, corner, rows, west)
+		divide(m, rng, opts, Cell{corner.Row, x + 1}, rows, cols-west)
+	}
+}
+
+// horizontal decides which way to cut a chamber. Chambers one cell thick can
+// only be cut one way.
+func horizontal(rng *rand.Rand, bias float64, rows, cols int) bool {
+	if rows < 2 {
+		return false
+	}
+	if cols < 2 {
+		return true
+	}
+	p := 0.5
+	if rows > cols {
+		p = 1
+	}
+	if rows < cols {
+		p = 0
+	}
+	if bias > 0 {
+		p += bias * (1 - p)
+	}
+	if bias < 0 {
+		p *= 1 + bias
+	}
+	return rng.Float64() < p
+}
@@ -0,0 +1,30 @@
+package gen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestDivision_Rooms(t *testing.T) {
+	m := wall.NewMaze(20, 30)
+	Division(DivisionOptions{RoomSize: 4})(m, rand.New(rand.NewSource(1)))
+	require.Equal(t, 20*30, countReachable(m, 0, 0), "not fully connected\n%v", m)
+	require.Greater(t, countOpen(m), 20*30-1, "expected rooms with loops\n%v", m)
+}
+
+func TestDivision_Bias(t *testing.T) {
+	// With full horizontal bias, every cut spans the whole width until the
+	// chambers are a single row tall, so no vertical walls remain.
+	m := wall.NewMaze(10, 10)
+	Division(DivisionOptions{Bias: 1})(m, rand.New(rand.NewSource(1)))
+	requirePerfect(t, m)
+	for r := 0; r < m.Rows(); r++ {
+		for c := 0; c+1 < m.Cols(); c++ {
+			require.True(t, m.IsOpen(r, c, wall.East), "vertical wall at %d,%d\n%v", r, c, m)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package gen
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func init() {
+	Register("eller", Eller())
+}
+
+// Props to [1] for helping me understand Eller's algorithm!
+// [1]: https://weblog.jamisbuck.org/2010/12/29/maze-generation-eller-s-algorithm
+
+// EllerParams shape the texture of an Eller maze.
+type EllerParams struct {
+	// Merge is the probability of joining adjacent cells from different groups.
+	// Higher values make long horizontal runs.
+	Merge float64
+	// Vertical decides how many cells of each group open to the south. Nil
+	// means UniformCount.
+	Vertical VerticalPolicy
+}
+
+// DefaultEllerParams are the values Buck used.
+var DefaultEllerParams = EllerParams{Merge: 0.5, Vertical: UniformCount()}
+
+// EllerBand applies Params to every row from From until the next band.
+type EllerBand struct {
+	From   int
+	Params EllerParams
+}
+
+// VerticalPolicy returns how many cells, between 1 and groupSize, of one group
+// should open to the south. The cells themselves are picked at random.
+type VerticalPolicy func(rng *rand.Rand, groupSize int) int
+
+// ExactlyOne opens a single cell per group, making long vertical corridors
+// rare.
+func ExactlyOne() VerticalPolicy {
+	return func(*rand.Rand, int) int { return 1 }
+}
+
+// UniformCount opens between one and all cells of each group, uniformly.
+func UniformCount() VerticalPolicy {
+	return func(rng *rand.Rand, n int) int { return 1 + rng.Intn(n) }
+}
+
+// PerCell opens each cell with probability p, but always at least one.
+func PerCell(p float64) VerticalPolicy {
+	return func(rng *rand.Rand, n int) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			if rng.Float64() < p {
+				count++
+			}
+		}
+		if count == 0 {
+			return 1
+		}
+		return count
+	}
+}
+
+// ParseVertical parses a vertical policy: "one", "uniform", or "cell:P" for
+// PerCell(P).
+func ParseVertical(s string) (VerticalPolicy, error) {
+	switch {
+	case s == "one":
+		return ExactlyOne(), nil
+	case s == "uniform":
+		return UniformCount(), nil
+	case strings.HasPrefix(s, "cell:"):
+		p, err := parseProbability(strings.TrimPrefix(s, "cell:"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad probability in %q", s)
+		}
+		return PerCell(p), nil
+	}
+	return nil, errors.Errorf("unknown vertical policy %q", s)
+}
+
+// parseProbability parses a number between 0 and 1.
+func parseProbability(s string) (float64, error) {
+	p, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if !(p >= 0 && p <= 1) {
+		return 0, errors.Errorf("%v is not between 0 and 1", p)
+	}
+	return p, nil
+}
+
+// ParseEllerBands parses a semicolon-separated list of ROW,MERGE,VERTICAL
+// bands, e.g. "0,0.9,one;20,0.3,cell:0.5". See ParseVertical for the format of
+// VERTICAL.
+func ParseEllerBands(s string) ([]EllerBand, error) {
+	var bands []EllerBand
+	for _, part := range strings.Split(s, ";") {
+		fields := strings.Split(strings.TrimSpace(part), ",")
+		if len(fields) != 3 {
+			return nil, errors.Errorf("band %q should be ROW,MERGE,VERTICAL", part)
+		}
+		row, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad row in %q", part)
+		}
+		merge, err := parseProbability(fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad merge probability in %q", part)
+		}
+		vertical, err := ParseVertical(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		if len(bands) > 0 && row <= bands[len(bands)-1].From {
+			return nil, errors.Errorf("band %q is out of order", part)
+		}
+		bands = append(bands, EllerBand{From: row, Params: EllerParams{Merge: merge, Vertical: vertical}})
+	}
+	return bands, nil
+}
+
+// ParseEllerOptions combines the usual command line options into bands. The
+// merge probability and vertical policy apply until the first of the optional
+// bands, which are parsed by ParseEllerBands.
+func ParseEllerOptions(merge float64, vertical, bands string) ([]EllerBand, error) {
+	if !(merge >= 0 && merge <= 1) {
+		return nil, errors.Errorf("merge probability %v is not between 0 and 1", merge)
+	}
+	v, err := ParseVertical(vertical)
+	if err != nil {
+		return nil, err
+	}
+	base := []EllerBand{{From: 0, Params: EllerParams{Merge: merge, Vertical: v}}}
+	if len(bands) == 0 {
+		return base, nil
+	}
+	rest, err := ParseEllerBands(bands)
+	if err != nil {
+		return nil, err
+	}
+	if rest[0].From == 0 {
+		return rest, nil
+	}
+	return append(base, rest...), nil
+}
+
+// ParamsFor returns the parameters for the given row: those of the last band
+// starting at or before it, or DefaultEllerParams if there is none.
+func ParamsFor(bands []EllerBand, row int) EllerParams {
+	params := DefaultEllerParams
+	for _, b := range bands {
+		if b.From > row {
+			break
+		}
+		params = b.Params
+	}
+	if params.Vertical == nil {
+		params.Vertical = UniformCount()
+	}
+	return params
+}
+
+// Eller returns a generator using Eller's algorithm, which works one row at a
+// time and only keeps a single row in memory. Bands, sorted by From, tune the
+// algorithm for different parts of the maze. Without any, it uses
+// DefaultEllerParams throughout.
+func Eller(bands ...EllerBand) Generator {
+	return func(m *wall.Maze, rng *rand.Rand) {
+		if m.Rows() == 0 || m.Cols() == 0 {
+			return
+		}
+		s := newEllerState(m.Cols(), rng)
+		for r := 0; r < m.Rows(); r++ {
+			lastRow := r+1 == m.Rows()
+			s.compute(lastRow, ParamsFor(bands, r))
+			// Copy computed row into the wall.Maze
+			for c := 0; c < m.Cols(); c++ {
+				focus(m, Cell{r, c}, nil)
+				if s.openEast[c] {
+					m.Open(r, c, wall.East)
+				}
+				if s.openSouth[c] {
+					m.Open(r, c, wall.South)
+				}
+			}
+			s.nextRow()
+		}
+	}
+}
+
+// ellerState represents one row of the maze, which is all that the Eller
+// algorithm needs in memory at any time.
+type ellerState struct {
+	rng *rand.Rand
+	// list of group IDs indexed by cell position
+	groupIDs []int
+	// list of cell positions indexed by group ID
+	groups map[int][]int
+	// Whether the east/south wall is open for the cell at that position
+	openEast  []bool
+	openSouth []bool
+}
+
+// newEllerState instantiates a fresh state.
+func newEllerState(cols int, rng *rand.Rand) *ellerState {
+	s := &ellerState{
+		rng:       rng,
+		groupIDs:  make([]int, cols),
+		groups:    make(map[int][]int),
+		openEast:  make([]bool, cols),
+		openSouth: make([]bool, cols),
+	}
+	s.nextRow()
+	return s
+}
+
+// compute randomly removes walls between cells, causing groups to merge, then
+// randomly selects 1 or more cell from each group to advance to the next row
+// (by removing its south wall).
+func (s *ellerState) compute(lastRow bool, params EllerParams) {
+	for i := 0; i < len(s.groupIDs)-1; i++ {
+		if s.groupIDs[i] == s.groupIDs[i+1] {
+			continue
+		}
+		// Buck used 50% chance of joining adjacent, nonmatching neighbors.
+		// Now it's a parameter.
+		// On the last row, we connect all isolated subsections of the maze.
+		if lastRow || s.rng.Float64() < params.Merge {
+			// log.Printf("Merge %v and %v", i, i+1)
+			s.openEast[i] = true
+			s.replace(s.groupIDs[i+1], s.groupIDs[i])
+		}
+	}
+	if lastRow {
+		return
+	}
+	for _, id := range s.sortedGroupIDs() {
+		group := s.groups[id]
+		// Buck chose a uniformly random number of cells from each set to propagate
+		// down, with minimum 1 and maximum all. That is now UniformCount.
+		propagate := params.Vertical(s.rng, len(group))
+		s.rng.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+		for _, pos := range group[:propagate] {
+			// log.Printf("Propagate %v", pos)
+			s.openSouth[pos] = true
+		}
+	}
+}
+
+// sortedGroupIDs lists group IDs in order of their first cell, so that a seeded
+// rng always produces the same maze regardless of map iteration order.
+func (s *ellerState) sortedGroupIDs() []int {
+	var ids []int
+	seen := map[int]bool{}
+	for _, id := range s.groupIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// replace merges two groups, replacing all references to the old group with the
+// new group.
+func (s *ellerState) replace(old, new int) {
+	s.groups[new] = append(s.groups[new], s.groups[old]...)
+	delete(s.groups, old)
+	for i := range s.groupIDs {
+		if s.groupIDs[i] == old {
+			s.groupIDs[i] = new
+		}
+	}
+}
+
+// nextRow resets the state in preparation for computing the next row, by
+// - creating new group IDs for cells that don't advance to the next row
+// - resetting all walls
+func (s *ellerState) nextRow() {
+	for i := range s.groupIDs {
+		if !s.openSouth[i] {
+			s.removeOne(i)
+		}
+		s.openEast[i] = false
+		s.openSouth[i] = false
+	}
+	nextID := max(s.groupIDs) + 1
+	for i := range s.groupIDs {
+		if s.groupIDs[i] != 0 {
+			continue
+		}
+		s.groupIDs[i] = nextID
+		s.groups[nextID] = []int{i}
+		nextID++
+	}
+}
+
+// removeOne removes a single cell position from a group, and sets that cell's
+// group to 0 (invalid). This is common when some members of a group do not
+// advance to the next row. If a group becomes empty, it is completely deleted.
+func (s *ellerState) removeOne(pos int) {
+	groupID := s.groupIDs[pos]
+	newGroup := []int{}
+	for _, p := range s.groups[groupID] {
+		if p == pos {
+			continue
+		}
+		newGroup = append(newGroup, p)
+	}
+	if len(newGroup) == 0 {
+		delete(s.groups, groupID)
+	} else {
+		s.groups[groupID] = newGroup
+	}
+	s.groupIDs[pos] = 0
+}
+
+func max(vs []int) int {
+	if len(vs) == 0 {
+		return 0
+	}
+	val := vs[0]
+	for _, v := range vs {
+		if v > val {
+			val = v
+		}
+	}
+	return val
+}
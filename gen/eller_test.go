@@ -0,0 +1,86 @@
+package gen
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestParseEllerBands(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []int // From of each band
+		wantErr bool
+	}{
+		{in: "0,0.5,uniform", want: []int{0}},
+		{in: "0,0.9,one;20,0.3,cell:0.5", want: []int{0, 20}},
+		{in: "5,1,one; 6,0,uniform", want: []int{5, 6}},
+		{in: "", wantErr: true},
+		{in: "0,0.5", wantErr: true},
+		{in: "x,0.5,one", wantErr: true},
+		{in: "0,half,one", wantErr: true},
+		{in: "0,0.5,all", wantErr: true},
+		{in: "0,0.5,cell:x", wantErr: true},
+		{in: "10,0.5,one;5,0.5,one", wantErr: true},
+		{in: "0,7,one", wantErr: true},
+		{in: "0,-0.1,one", wantErr: true},
+		{in: "0,NaN,one", wantErr: true},
+		{in: "0,0.5,cell:-1", wantErr: true},
+		{in: "0,0.5,cell:1.5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			bands, err := ParseEllerBands(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var got []int
+			for _, b := range bands {
+				got = append(got, b.From)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseEllerOptions(t *testing.T) {
+	_, err := ParseEllerOptions(0.5, "cell:0.25", "10,1,one")
+	require.NoError(t, err)
+	for _, merge := range []float64{-0.5, 7, math.NaN()} {
+		_, err := ParseEllerOptions(merge, "uniform", "")
+		require.Error(t, err, "merge %v", merge)
+	}
+	_, err = ParseEllerOptions(0.5, "cell:2", "")
+	require.Error(t, err)
+}
+
+func TestEller_Bands(t *testing.T) {
+	// Rows 0-4 are corridors with one exit. Rows 5+ never merge sideways, so
+	// every cell must open south to stay connected.
+	corridor := EllerParams{Merge: 1, Vertical: ExactlyOne()}
+	columns := EllerParams{Merge: 0, Vertical: PerCell(1)}
+	m := wall.NewMaze(10, 8)
+	Eller(EllerBand{0, corridor}, EllerBand{5, columns})(m, rand.New(rand.NewSource(1)))
+	requirePerfect(t, m)
+	for r := 0; r < 5; r++ {
+		south := 0
+		for c := 0; c < m.Cols(); c++ {
+			require.True(t, c+1 == m.Cols() || m.IsOpen(r, c, wall.East), "closed at %d,%d\n%v", r, c, m)
+			if m.IsOpen(r, c, wall.South) {
+				south++
+			}
+		}
+		require.Equal(t, 1, south, "row %d\n%v", r, m)
+	}
+	for r := 5; r+1 < m.Rows(); r++ {
+		for c := 0; c < m.Cols(); c++ {
+			require.True(t, m.IsOpen(r, c, wall.South), "closed at %d,%d\n%v", r, c, m)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Package gen contains maze generation algorithms that operate on wall.Maze.
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+// Generator carves passages into a maze by opening walls. Generators expect a
+// maze with every wall closed, such as one fresh from wall.NewMaze, and leave
+// behind a perfect maze (exactly one path between any two cells) unless noted
+// otherwise.
+type Generator func(m *wall.Maze, rng *rand.Rand)
+
+var registry = map[string]Generator{}
+
+// Register makes a generator available by name. It is meant to be called from
+// init, so it panics if the name is already taken.
+func Register(name string, g Generator) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("generator %q registered twice", name))
+	}
+	registry[name] = g
+}
+
+// Lookup returns the generator registered under name.
+func Lookup(name string) (Generator, bool) {
+	g, ok := registry[name]
+	return g, ok
+}
+
+// Names returns the names of all registered generators in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cell is a position in a maze.
+type Cell struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// Focuser can be implemented by a maze's wall.Observer to also learn which
+// cell a generator is working on, and which cells it is considering next. The
+// frontier slice is only valid during the call.
+type Focuser interface {
+	Focus(current Cell, frontier []Cell)
+}
+
+// focuser returns the maze observer's Focuser, if it has one. Generators check
+// this before building a frontier just to report it. For a wall.Maze Sub view,
+// the parent's Focuser is returned, translated to the parent's coordinates.
+func focuser(m *wall.Maze) Focuser {
+	o := m.Observer()
+	var row, col int
+	for {
+		off, ok := o.(offsetter)
+		if !ok {
+			break
+		}
+		var r, c int
+		o, r, c = off.Offset()
+		row, col = row+r, col+c
+	}
+	f, ok := o.(Focuser)
+	if !ok {
+		return nil
+	}
+	if row == 0 && col == 0 {
+		return f
+	}
+	return offsetFocuser{f, row, col}
+}
+
+// offsetter is implemented by the observer of a wall.Maze Sub view.
+type offsetter interface {
+	Offset() (parent wall.Observer, row, col int)
+}
+
+// offsetFocuser translates focus in a Sub view to its parent's coordinates.
+type offsetFocuser struct {
+	f        Focuser
+	row, col int
+}
+
+func (o offsetFocuser) Focus(current Cell, frontier []Cell) {
+	shift := func(c Cell) Cell { return Cell{c.Row + o.row, c.Col + o.col} }
+	shifted := make([]Cell, len(frontier))
+	for i, c := range frontier {
+		shifted[i] = shift(c)
+	}
+	o.f.Focus(shift(current), shifted)
+}
+
+// focus reports the generator's current cell and frontier, if anyone is
+// listening.
+func focus(m *wall.Maze, current Cell, frontier []Cell) {
+	if f := focuser(m); f != nil {
+		f.Focus(current, frontier)
+	}
+}
+
+// index flattens a cell position for use with disjoint.Set.
+func index(m *wall.Maze, row, col int) int { return row*m.Cols() + col }
@@ -0,0 +1,83 @@
+package gen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestRegistered(t *testing.T) {
+	sizes := []struct{ rows, cols int }{{1, 1}, {1, 8}, {8, 1}, {2, 2}, {7, 13}, {20, 20}}
+	for _, name := range Names() {
+		g, ok := Lookup(name)
+		require.True(t, ok)
+		t.Run(name, func(t *testing.T) {
+			for _, size := range sizes {
+				for seed := int64(1); seed <= 3; seed++ {
+					m := wall.NewMaze(size.rows, size.cols)
+					g(m, rand.New(rand.NewSource(seed)))
+					requirePerfect(t, m)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistered_Empty(t *testing.T) {
+	for _, name := range Names() {
+		g, _ := Lookup(name)
+		for _, size := range []struct{ rows, cols int }{{0, 0}, {0, 5}, {5, 0}} {
+			m := wall.NewMaze(size.rows, size.cols)
+			require.NotPanics(t, func() { g(m, rand.New(rand.NewSource(1))) }, "%s on %dx%d", name, size.rows, size.cols)
+			require.Zero(t, countOpen(m))
+		}
+	}
+}
+
+// requirePerfect fails the test unless every cell is reachable and there are
+// no loops. A connected graph with cells-1 edges is a tree.
+func requirePerfect(t *testing.T, m *wall.Maze) {
+	t.Helper()
+	cells := m.Rows() * m.Cols()
+	require.Equal(t, cells-1, countOpen(m), "wrong number of openings in\n%v", m)
+	require.Equal(t, cells, countReachable(m, 0, 0), "not fully connected\n%v", m)
+}
+
+func countOpen(m *wall.Maze) int {
+	n := 0
+	for r := 0; r < m.Rows(); r++ {
+		for c := 0; c < m.Cols(); c++ {
+			if m.IsOpen(r, c, wall.East) {
+				n++
+			}
+			if m.IsOpen(r, c, wall.South) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func countReachable(m *wall.Maze, row, col int) int {
+	seen := make([]bool, m.Rows()*m.Cols())
+	seen[index(m, row, col)] = true
+	stack := [][2]int{{row, col}}
+	n := 0
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n++
+		for _, d := range wall.Directions {
+			r, c, ok := m.Neighbor(cur[0], cur[1], d)
+			if !ok || !m.IsOpen(cur[0], cur[1], d) || seen[index(m, r, c)] {
+				continue
+			}
+			seen[index(m, r, c)] = true
+			stack = append(stack, [2]int{r, c})
+		}
+	}
+	return n
+}
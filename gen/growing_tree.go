@@ -0,0 +1,132 @@
+package gen
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func init() {
+	Register("growing-tree", GrowingTree(Mix(Weighted{75, Newest()}, Weighted{25, Random()})))
+}
+
+// Selector picks which of the growing tree's n active cells to extend next. The
+// active cells are ordered from oldest to newest.
+type Selector func(rng *rand.Rand, n int) int
+
+// Newest always extends the most recently added cell. This makes the growing
+// tree behave like a recursive backtracker: long, winding corridors.
+func Newest() Selector { return func(_ *rand.Rand, n int) int { return n - 1 } }
+
+// Oldest always extends the least recently added cell, producing long straight
+// corridors radiating from the start.
+func Oldest() Selector { return func(_ *rand.Rand, n int) int { return 0 } }
+
+// Random extends a random active cell. This makes the growing tree behave like
+// simplified Prim's: short corridors and many dead ends.
+func Random() Selector { return func(rng *rand.Rand, n int) int { return rng.Intn(n) } }
+
+// Weighted pairs a Selector with its relative weight in a Mix.
+type Weighted struct {
+	Weight   float64
+	Selector Selector
+}
+
+// Mix returns a Selector which delegates each choice to one of the given
+// selectors at random, in proportion to their weights.
+func Mix(parts ...Weighted) Selector {
+	total := 0.0
+	for _, p := range parts {
+		total += p.Weight
+	}
+	return func(rng *rand.Rand, n int) int {
+		x := rng.Float64() * total
+		for _, p := range parts {
+			if x < p.Weight {
+				return p.Selector(rng, n)
+			}
+			x -= p.Weight
+		}
+		// Only reachable through floating point rounding.
+		return parts[len(parts)-1].Selector(rng, n)
+	}
+}
+
+var selectors = map[string]func() Selector{
+	"newest": Newest,
+	"oldest": Oldest,
+	"random": Random,
+}
+
+// ParseSelector parses a selection policy such as "newest" or
+// "newest:75,random:25". Each comma-separated part names a policy (newest,
+// oldest, random) with an optional positive weight, which defaults to 1.
+func ParseSelector(s string) (Selector, error) {
+	var parts []Weighted
+	for _, part := range strings.Split(s, ",") {
+		name, weight := strings.TrimSpace(part), 1.0
+		if i := strings.Index(name, ":"); i >= 0 {
+			var err error
+			weight, err = strconv.ParseFloat(name[i+1:], 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "bad weight in %q", part)
+			}
+			if !(weight > 0) || math.IsInf(weight, 1) {
+				return nil, errors.Errorf("weight in %q should be a positive number", part)
+			}
+			name = name[:i]
+		}
+		sel, ok := selectors[name]
+		if !ok {
+			return nil, errors.Errorf("unknown selection policy %q", name)
+		}
+		parts = append(parts, Weighted{weight, sel()})
+	}
+	if len(parts) == 1 {
+		return parts[0].Selector, nil
+	}
+	return Mix(parts...), nil
+}
+
+// GrowingTree returns a generator using the growing tree algorithm. It keeps a
+// list of active cells, starting with one random cell. Each step, sel picks an
+// active cell and a wall is opened into a random unvisited neighbor, which
+// becomes active too. Cells with no unvisited neighbors are dropped.
+func GrowingTree(sel Selector) Generator {
+	return func(m *wall.Maze, rng *rand.Rand) {
+		if m.Rows() == 0 || m.Cols() == 0 {
+			return
+		}
+		visited := make([]bool, m.Rows()*m.Cols())
+		start := Cell{rng.Intn(m.Rows()), rng.Intn(m.Cols())}
+		visited[index(m, start.Row, start.Col)] = true
+		active := []Cell{start}
+		for len(active) > 0 {
+			i := sel(rng, len(active))
+			cur := active[i]
+			var ds []wall.Direction
+			for _, d := range wall.Directions {
+				r, c, ok := m.Neighbor(cur.Row, cur.Col, d)
+				if ok && !visited[index(m, r, c)] {
+					ds = append(ds, d)
+				}
+			}
+			if len(ds) == 0 {
+				// Keep the list in order, since selectors depend on it.
+				active = append(active[:i], active[i+1:]...)
+				continue
+			}
+			d := ds[rng.Intn(len(ds))]
+			focus(m, cur, active)
+			m.Open(cur.Row, cur.Col, d)
+			r, c, _ := m.Neighbor(cur.Row, cur.Col, d)
+			visited[index(m, r, c)] = true
+			active = append(active, Cell{r, c})
+		}
+	}
+}
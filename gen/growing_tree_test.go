@@ -0,0 +1,57 @@
+package gen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{in: "newest"},
+		{in: "oldest"},
+		{in: "random"},
+		{in: "newest:75,random:25"},
+		{in: "newest:3, oldest, random:0.5"},
+		{in: "", wantErr: true},
+		{in: "middle", wantErr: true},
+		{in: "newest:lots", wantErr: true},
+		{in: "newest:-1,random", wantErr: true},
+		{in: "newest:0,random", wantErr: true},
+		{in: "newest:0,random:0", wantErr: true},
+		{in: "newest:NaN,random", wantErr: true},
+		{in: "newest:Inf,random", wantErr: true},
+		{in: "newest:+Inf", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			sel, err := ParseSelector(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			m := wall.NewMaze(9, 11)
+			GrowingTree(sel)(m, rand.New(rand.NewSource(1)))
+			requirePerfect(t, m)
+		})
+	}
+}
+
+func TestMix(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	sel := Mix(Weighted{3, Newest()}, Weighted{1, Oldest()})
+	newest := 0
+	for i := 0; i < 4000; i++ {
+		if sel(rng, 10) == 9 {
+			newest++
+		}
+	}
+	require.InDelta(t, 3000, newest, 150)
+}
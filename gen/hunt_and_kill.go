@@ -0,0 +1,136 @@
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/pkg/errors"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func init() {
+	Register("hunt-and-kill", HuntAndKill(HuntAndKillOptions{}))
+}
+
+// ScanOrder is the order in which hunt-and-kill searches for its next cell.
+type ScanOrder int
+
+// The supported scan orders.
+const (
+	// RowMajor scans each row from west to east, top to bottom.
+	RowMajor ScanOrder = iota
+	// Serpentine scans like RowMajor but alternates direction on every row.
+	Serpentine
+	// RandomScan scans the cells in a fresh random order for every hunt.
+	RandomScan
+)
+
+var scanOrders = map[string]ScanOrder{
+	"row-major":  RowMajor,
+	"serpentine": Serpentine,
+	"random":     RandomScan,
+}
+
+// ParseScanOrder parses one of "row-major", "serpentine" or "random".
+func ParseScanOrder(s string) (ScanOrder, error) {
+	o, ok := scanOrders[s]
+	if !ok {
+		return 0, errors.Errorf("unknown scan order %q", s)
+	}
+	return o, nil
+}
+
+// HuntAndKillOptions configures a hunt-and-kill generator.
+type HuntAndKillOptions struct {
+	Scan ScanOrder
+	// If Hunts is not nil, it is set to the number of hunt phases it took to
+	// finish the maze.
+	Hunts *int
+}
+
+// HuntAndKill returns a generator using the hunt-and-kill algorithm. It
+// random-walks through unvisited cells until it gets stuck, then scans for an
+// unvisited cell next to the visited region, joins it to the maze, and walks
+// again from there. The long corridors resemble a recursive backtracker's, but
+// no stack is needed.
+func HuntAndKill(opts HuntAndKillOptions) Generator {
+	return func(m *wall.Maze, rng *rand.Rand) {
+		if m.Rows() == 0 || m.Cols() == 0 {
+			return
+		}
+		visited := make([]bool, m.Rows()*m.Cols())
+		// neighbors returns the directions from cur to cells that are (or are
+		// not) visited.
+		neighbors := func(cur Cell, want bool) []wall.Direction {
+			var ds []wall.Direction
+			for _, d := range wall.Directions {
+				r, c, ok := m.Neighbor(cur.Row, cur.Col, d)
+				if ok && visited[index(m, r, c)] == want {
+					ds = append(ds, d)
+				}
+			}
+			return ds
+		}
+
+		order := scanOrder(m, opts.Scan)
+		skip := 0 // every cell in order[:skip] is visited
+		hunts := 0
+		cur := Cell{rng.Intn(m.Rows()), rng.Intn(m.Cols())}
+		for {
+			// Kill phase: walk until stuck.
+			visited[index(m, cur.Row, cur.Col)] = true
+			if ds := neighbors(cur, false); len(ds) > 0 {
+				d := ds[rng.Intn(len(ds))]
+				focus(m, cur, nil)
+				m.Open(cur.Row, cur.Col, d)
+				cur.Row, cur.Col, _ = m.Neighbor(cur.Row, cur.Col, d)
+				continue
+			}
+
+			// Hunt phase: find an unvisited cell bordering the maze.
+			hunts++
+			if opts.Scan == RandomScan {
+				order = rng.Perm(len(visited))
+			} else {
+				for skip < len(order) && visited[order[skip]] {
+					skip++
+				}
+			}
+			found := false
+			for _, i := range order[skip:] {
+				if visited[i] {
+					continue
+				}
+				cur = Cell{i / m.Cols(), i % m.Cols()}
+				if ds := neighbors(cur, true); len(ds) > 0 {
+					focus(m, cur, nil)
+					m.Open(cur.Row, cur.Col, ds[rng.Intn(len(ds))])
+					found = true
+					break
+				}
+			}
+			if !found {
+				break
+			}
+		}
+		if opts.Hunts != nil {
+			*opts.Hunts = hunts
+		}
+	}
+}
+
+// scanOrder lists cell indices in the given order. RandomScan gets a new order
+// for each hunt, so it starts out as row-major.
+func scanOrder(m *wall.Maze, o ScanOrder) []int {
+	order := make([]int, 0, m.Rows()*m.Cols())
+	for r := 0; r < m.Rows(); r++ {
+		for c := 0; c < m.Cols(); c++ {
+			if o == Serpentine && r%2 == 1 {
+				order = append(order, index(m, r, m.Cols()-1-c))
+			} else {
+				order = append(order, index(m, r, c))
+			}
+		}
+	}
+	return order
+}
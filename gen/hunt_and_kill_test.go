@@ -0,0 +1,33 @@
+package gen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestHuntAndKill_Scan(t *testing.T) {
+	for name := range scanOrders {
+		t.Run(name, func(t *testing.T) {
+			scan, err := ParseScanOrder(name)
+			require.NoError(t, err)
+			hunts := -1
+			m := wall.NewMaze(15, 15)
+			HuntAndKill(HuntAndKillOptions{Scan: scan, Hunts: &hunts})(m, rand.New(rand.NewSource(1)))
+			requirePerfect(t, m)
+			// The final hunt always comes up empty.
+			require.GreaterOrEqual(t, hunts, 1)
+		})
+	}
+	_, err := ParseScanOrder("diagonal")
+	require.Error(t, err)
+}
+
+func TestHuntAndKill_OneCell(t *testing.T) {
+	hunts := -1
+	HuntAndKill(HuntAndKillOptions{Hunts: &hunts})(wall.NewMaze(1, 1), rand.New(rand.NewSource(1)))
+	require.Equal(t, 1, hunts)
+}
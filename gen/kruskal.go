@@ -0,0 +1,48 @@
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/misterikkit/automata/disjoint"
+	"github.com/misterikkit/automata/wall"
+)
+
+func init() {
+	Register("kruskal", Kruskal())
+}
+
+// Kruskal returns a generator using randomized Kruskal's algorithm. Every
+// interior wall is visited in random order, and opened if the cells on either
+// side are not already connected.
+func Kruskal() Generator {
+	return func(m *wall.Maze, rng *rand.Rand) {
+		type edge struct {
+			row, col int
+			d        wall.Direction // East or South, so each wall appears once
+		}
+		var edges []edge
+		for r := 0; r < m.Rows(); r++ {
+			for c := 0; c < m.Cols(); c++ {
+				if c+1 < m.Cols() {
+					edges = append(edges, edge{r, c, wall.East})
+				}
+				if r+1 < m.Rows() {
+					edges = append(edges, edge{r, c, wall.South})
+				}
+			}
+		}
+		rng.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+
+		sets := disjoint.New(m.Rows() * m.Cols())
+		for _, e := range edges {
+			nextRow, nextCol, _ := m.Neighbor(e.row, e.col, e.d)
+			if sets.Union(index(m, e.row, e.col), index(m, nextRow, nextCol)) {
+				focus(m, Cell{e.row, e.col}, nil)
+				m.Open(e.row, e.col, e.d)
+			}
+			if sets.Count() == 1 {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package gen
+
+import (
+	"container/heap"
+	"math/rand"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func init() {
+	Register("prim", Prim(PrimOptions{Weighted: true}))
+	Register("prim-simple", Prim(PrimOptions{}))
+}
+
+// PrimOptions configures a Prim generator.
+type PrimOptions struct {
+	// Start is the cell the maze grows outward from. It is clamped to the maze,
+	// so a Start outside it grows from the nearest cell on the border.
+	Start Cell
+	// Weighted selects "true" Prim's algorithm, where every passage has a random
+	// weight and the cheapest one on the frontier is always opened next. When
+	// false, a random frontier cell is joined to a random visited neighbor
+	// instead (simplified Prim's), which is faster and even more radial.
+	Weighted bool
+	// If History is not nil, a snapshot of the frontier is appended to it every
+	// time a cell joins the maze. This is slow and only meant for visualization.
+	History *[][]Cell
+}
+
+// Prim returns a generator using randomized Prim's algorithm. Both variants
+// produce short corridors with many dead ends.
+func Prim(opts PrimOptions) Generator {
+	if opts.Weighted {
+		return primWeighted(opts)
+	}
+	return primSimple(opts)
+}
+
+func primSimple(opts PrimOptions) Generator {
+	return func(m *wall.Maze, rng *rand.Rand) {
+		if m.Rows() == 0 || m.Cols() == 0 {
+			return
+		}
+		visited := make([]bool, m.Rows()*m.Cols())
+		queued := make([]bool, m.Rows()*m.Cols())
+		var frontier []Cell
+		add := func(cur Cell) {
+			visited[index(m, cur.Row, cur.Col)] = true
+			for _, d := range wall.Directions {
+				r, c, ok := m.Neighbor(cur.Row, cur.Col, d)
+				if !ok || visited[index(m, r, c)] || queued[index(m, r, c)] {
+					continue
+				}
+				queued[index(m, r, c)] = true
+				frontier = append(frontier, Cell{r, c})
+			}
+			if opts.History != nil {
+				*opts.History = append(*opts.History, append([]Cell(nil), frontier...))
+			}
+		}
+
+		add(clamp(m, opts.Start))
+		for len(frontier) > 0 {
+			i := rng.Intn(len(frontier))
+			cur := frontier[i]
+			frontier[i] = frontier[len(frontier)-1]
+			frontier = frontier[:len(frontier)-1]
+
+			// Join to a random neighbor that is already part of the maze.
+			var in []wall.Direction
+			for _, d := range wall.Directions {
+				r, c, ok := m.Neighbor(cur.Row, cur.Col, d)
+				if ok && visited[index(m, r, c)] {
+					in = append(in, d)
+				}
+			}
+			focus(m, cur, frontier)
+			m.Open(cur.Row, cur.Col, in[rng.Intn(len(in))])
+			add(cur)
+		}
+	}
+}
+
+func primWeighted(opts PrimOptions) Generator {
+	return func(m *wall.Maze, rng *rand.Rand) {
+		if m.Rows() == 0 || m.Cols() == 0 {
+			return
+		}
+		visited := make([]bool, m.Rows()*m.Cols())
+		var edges edgeHeap
+		add := func(cur Cell) {
+			visited[index(m, cur.Row, cur.Col)] = true
+			for _, d := range wall.Directions {
+				r, c, ok := m.Neighbor(cur.Row, cur.Col, d)
+				if !ok || visited[index(m, r, c)] {
+					continue
+				}
+				// Each passage is pushed at most once, from whichever side is visited
+				// first, so picking its weight now is as good as picking it up front.
+				heap.Push(&edges, weightedEdge{from: cur, d: d, to: Cell{r, c}, weight: rng.Float64()})
+			}
+			if opts.History != nil {
+				*opts.History = append(*opts.History, edges.frontier(visited, m))
+			}
+		}
+
+		add(clamp(m, opts.Start))
+		for edges.Len() > 0 {
+			e := heap.Pop(&edges).(weightedEdge)
+			if visited[index(m, e.to.Row, e.to.Col)] {
+				continue
+			}
+			if f := focuser(m); f != nil {
+				f.Focus(e.to, edges.frontier(visited, m))
+			}
+			m.Open(e.from.Row, e.from.Col, e.d)
+			add(e.to)
+		}
+	}
+}
+
+// clamp returns the cell of m nearest to c.
+func clamp(m *wall.Maze, c Cell) Cell {
+	if c.Row < 0 {
+		c.Row = 0
+	} else if c.Row >= m.Rows() {
+		c.Row = m.Rows() - 1
+	}
+	if c.Col < 0 {
+		c.Col = 0
+	} else if c.Col >= m.Cols() {
+		c.Col = m.Cols() - 1
+	}
+	return c
+}
+
+type weightedEdge struct {
+	from, to Cell
+	d        wall.Direction // from -> to
+	weight   float64
+}
+
+// edgeHeap is a min-heap of edges for use with container/heap.
+type edgeHeap []weightedEdge
+
+func (h edgeHeap) Len() int            { return len(h) }
+func (h edgeHeap) Less(i, j int) bool  { return h[i].weight < h[j].weight }
+func (h edgeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *edgeHeap) Push(x interface{}) { *h = append(*h, x.(weightedEdge)) }
+func (h *edgeHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// frontier returns the distinct unvisited cells the heap can still reach.
+func (h edgeHeap) frontier(visited []bool, m *wall.Maze) []Cell {
+	seen := map[Cell]bool{}
+	var cells []Cell
+	for _, e := range h {
+		if visited[index(m, e.to.Row, e.to.Col)] || seen[e.to] {
+			continue
+		}
+		seen[e.to] = true
+		cells = append(cells, e.to)
+	}
+	return cells
+}
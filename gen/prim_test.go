@@ -0,0 +1,36 @@
+package gen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestPrim_History(t *testing.T) {
+	for _, weighted := range []bool{false, true} {
+		var history [][]Cell
+		m := wall.NewMaze(6, 9)
+		Prim(PrimOptions{Start: Cell{3, 4}, Weighted: weighted, History: &history})(m, rand.New(rand.NewSource(1)))
+		requirePerfect(t, m)
+
+		// One snapshot per cell. The first is the start cell's neighbors and the
+		// last is empty.
+		require.Len(t, history, 6*9)
+		require.ElementsMatch(t, []Cell{{2, 4}, {3, 5}, {4, 4}, {3, 3}}, history[0])
+		require.Empty(t, history[len(history)-1])
+	}
+}
+
+func TestPrim_StartOutside(t *testing.T) {
+	for _, weighted := range []bool{false, true} {
+		var history [][]Cell
+		m := wall.NewMaze(6, 9)
+		Prim(PrimOptions{Start: Cell{-2, 20}, Weighted: weighted, History: &history})(m, rand.New(rand.NewSource(1)))
+		requirePerfect(t, m)
+		// Clamped to the top-right corner.
+		require.ElementsMatch(t, []Cell{{1, 8}, {0, 7}}, history[0])
+	}
+}
@@ -0,0 +1,108 @@
+package gen
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/misterikkit/automata/disjoint"
+	"github.com/misterikkit/automata/wall"
+)
+
+// Tiled returns a generator for very large mazes. It splits the maze into
+// tiles of at most tileRows x tileCols, and fills each tile with g on one of
+// the given number of goroutines (0 means GOMAXPROCS). The tiles are then
+// joined by opening one wall for each edge of a random spanning tree of the
+// tile grid, so if g makes perfect mazes, so does Tiled.
+func Tiled(g Generator, tileRows, tileCols, workers int) Generator {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return func(m *wall.Maze, rng *rand.Rand) {
+		rows := (m.Rows() + tileRows - 1) / tileRows
+		cols := (m.Cols() + tileCols - 1) / tileCols
+
+		type tile struct {
+			maze *wall.Maze
+			seed int64
+		}
+		// Seeds are drawn up front so the result does not depend on scheduling.
+		tiles := make(chan tile, rows*cols)
+		for r := 0; r < rows; r++ {
+			for c := 0; c < cols; c++ {
+				top, left := r*tileRows, c*tileCols
+				height, width := tileRows, tileCols
+				if top+height > m.Rows() {
+					height = m.Rows() - top
+				}
+				if left+width > m.Cols() {
+					width = m.Cols() - left
+				}
+				tiles <- tile{m.Sub(top, left, height, width), rng.Int63()}
+			}
+		}
+		close(tiles)
+
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for t := range tiles {
+					g(t.maze, rand.New(rand.NewSource(t.seed)))
+				}
+			}()
+		}
+
+		// Pick the stitches while the tiles are generating. Stitches only touch
+		// border walls, which the tiles cannot open.
+		type stitch struct {
+			row, col int
+			d        wall.Direction
+		}
+		var edges []stitch
+		for r := 0; r < rows; r++ {
+			for c := 0; c < cols; c++ {
+				if c+1 < cols {
+					edges = append(edges, stitch{r, c, wall.East})
+				}
+				if r+1 < rows {
+					edges = append(edges, stitch{r, c, wall.South})
+				}
+			}
+		}
+		rng.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+		sets := disjoint.New(rows * cols)
+		var stitches []stitch
+		for _, e := range edges {
+			nextRow, nextCol := e.row, e.col+1
+			if e.d == wall.South {
+				nextRow, nextCol = e.row+1, e.col
+			}
+			if !sets.Union(e.row*cols+e.col, nextRow*cols+nextCol) {
+				continue
+			}
+			// Pick a random spot along the shared border.
+			if e.d == wall.East {
+				r := e.row*tileRows + rng.Intn(min(tileRows, m.Rows()-e.row*tileRows))
+				stitches = append(stitches, stitch{r, nextCol*tileCols - 1, wall.East})
+			} else {
+				c := e.col*tileCols + rng.Intn(min(tileCols, m.Cols()-e.col*tileCols))
+				stitches = append(stitches, stitch{nextRow*tileRows - 1, c, wall.South})
+			}
+		}
+
+		wg.Wait()
+		for _, s := range stitches {
+			focus(m, Cell{s.row, s.col}, nil)
+			m.Open(s.row, s.col, s.d)
+		}
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,70 @@
+package gen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestTiled(t *testing.T) {
+	sizes := []struct{ rows, cols, tileRows, tileCols int }{
+		{10, 10, 5, 5},
+		{13, 17, 4, 6}, // ragged edges
+		{3, 40, 3, 1},
+		{9, 9, 20, 20}, // one tile
+	}
+	for _, size := range sizes {
+		m := wall.NewMaze(size.rows, size.cols)
+		Tiled(Wilson(), size.tileRows, size.tileCols, 3)(m, rand.New(rand.NewSource(1)))
+		requirePerfect(t, m)
+	}
+}
+
+func TestTiled_Deterministic(t *testing.T) {
+	var mazes []string
+	for _, workers := range []int{1, 4} {
+		m := wall.NewMaze(30, 30)
+		Tiled(Kruskal(), 7, 7, workers)(m, rand.New(rand.NewSource(1)))
+		mazes = append(mazes, m.String())
+	}
+	require.Equal(t, mazes[0], mazes[1])
+}
+
+func BenchmarkKruskal(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Kruskal()(wall.NewMaze(1000, 1000), rand.New(rand.NewSource(1)))
+	}
+}
+
+func BenchmarkTiled(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Tiled(Kruskal(), 100, 100, 0)(wall.NewMaze(1000, 1000), rand.New(rand.NewSource(1)))
+	}
+}
+
+// focusRecorder collects every cell a generator reports focus on.
+type focusRecorder struct{ cells []Cell }
+
+func (f *focusRecorder) Opened(int, int, wall.Direction) {}
+func (f *focusRecorder) Closed(int, int, wall.Direction) {}
+func (f *focusRecorder) Set(int, int, string)            {}
+func (f *focusRecorder) Focus(current Cell, frontier []Cell) {
+	f.cells = append(f.cells, current)
+	f.cells = append(f.cells, frontier...)
+}
+
+func TestSubFocus(t *testing.T) {
+	m := wall.NewMaze(10, 10)
+	f := &focusRecorder{}
+	m.Observe(f)
+	// A view of a view, so offsets add up.
+	sub := m.Sub(1, 2, 8, 7).Sub(2, 2, 5, 4)
+	Prim(PrimOptions{})(sub, rand.New(rand.NewSource(1)))
+	require.NotEmpty(t, f.cells)
+	for _, c := range f.cells {
+		require.True(t, c.Row >= 3 && c.Row < 8 && c.Col >= 4 && c.Col < 8, "%v outside the view", c)
+	}
+}
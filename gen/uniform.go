@@ -0,0 +1,92 @@
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func init() {
+	Register("aldous-broder", AldousBroder())
+	Register("wilson", Wilson())
+	Register("aldous-broder-wilson", Hybrid(1.0/3))
+}
+
+// AldousBroder returns a generator that random-walks over the whole maze,
+// opening a wall whenever it steps into a cell for the first time. It produces
+// a uniform spanning tree, but the walk spends a long time finding the last few
+// unvisited cells.
+func AldousBroder() Generator { return Hybrid(1) }
+
+// Wilson returns a generator using Wilson's algorithm. Starting from each cell
+// not yet in the maze, it random-walks until reaching the maze, erasing any
+// loops it made along the way, then carves that path. It produces a uniform
+// spanning tree, but the first walks are slow to find the tiny initial maze.
+func Wilson() Generator { return Hybrid(0) }
+
+// Hybrid returns a generator that runs Aldous-Broder until the given fraction
+// of cells are visited, then finishes with Wilson's algorithm. Each algorithm
+// is used in the phase where it is fast, and the result is still a uniform
+// spanning tree.
+func Hybrid(switchAt float64) Generator {
+	return func(m *wall.Maze, rng *rand.Rand) {
+		if m.Rows() == 0 || m.Cols() == 0 {
+			return
+		}
+		cells := m.Rows() * m.Cols()
+		visited := make([]bool, cells)
+
+		// Aldous-Broder phase. This also seeds the maze for Wilson's.
+		cur := Cell{rng.Intn(m.Rows()), rng.Intn(m.Cols())}
+		visited[index(m, cur.Row, cur.Col)] = true
+		count := 1
+		for count < cells && float64(count) < switchAt*float64(cells) {
+			d, next := randomNeighbor(m, rng, cur)
+			if !visited[index(m, next.Row, next.Col)] {
+				focus(m, cur, nil)
+				m.Open(cur.Row, cur.Col, d)
+				visited[index(m, next.Row, next.Col)] = true
+				count++
+			}
+			cur = next
+		}
+
+		// Wilson phase. Only the most recent exit from each cell is remembered,
+		// which erases loops for free.
+		exits := make([]wall.Direction, cells)
+		for _, i := range rng.Perm(cells) {
+			if visited[i] {
+				continue
+			}
+			start := Cell{i / m.Cols(), i % m.Cols()}
+			for cur := start; !visited[index(m, cur.Row, cur.Col)]; {
+				d, next := randomNeighbor(m, rng, cur)
+				exits[index(m, cur.Row, cur.Col)] = d
+				cur = next
+			}
+			for cur := start; !visited[index(m, cur.Row, cur.Col)]; {
+				d := exits[index(m, cur.Row, cur.Col)]
+				visited[index(m, cur.Row, cur.Col)] = true
+				focus(m, cur, nil)
+				m.Open(cur.Row, cur.Col, d)
+				cur.Row, cur.Col, _ = m.Neighbor(cur.Row, cur.Col, d)
+			}
+		}
+	}
+}
+
+// randomNeighbor picks a uniformly random direction that stays in the maze,
+// and returns it along with the cell in that direction.
+func randomNeighbor(m *wall.Maze, rng *rand.Rand, cur Cell) (wall.Direction, Cell) {
+	var ds [4]wall.Direction
+	n := 0
+	for _, d := range wall.Directions {
+		if _, _, ok := m.Neighbor(cur.Row, cur.Col, d); ok {
+			ds[n] = d
+			n++
+		}
+	}
+	d := ds[rng.Intn(n)]
+	r, c, _ := m.Neighbor(cur.Row, cur.Col, d)
+	return d, Cell{r, c}
+}
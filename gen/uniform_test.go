@@ -0,0 +1,31 @@
+package gen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestHybrid_Uniform(t *testing.T) {
+	// A 2x3 grid has exactly 15 spanning trees, which should come up equally
+	// often.
+	const trees, samples = 15, 15000
+	for _, switchAt := range []float64{0, 0.5, 1} {
+		rng := rand.New(rand.NewSource(1))
+		counts := map[string]int{}
+		for i := 0; i < samples; i++ {
+			m := wall.NewMaze(2, 3)
+			Hybrid(switchAt)(m, rng)
+			counts[m.String()]++
+		}
+		if len(counts) != trees {
+			t.Errorf("switchAt=%v: got %d distinct mazes, want %d", switchAt, len(counts), trees)
+		}
+		for maze, n := range counts {
+			if want := samples / trees; n < want*8/10 || n > want*12/10 {
+				t.Errorf("switchAt=%v: maze seen %d times, want about %d\n%v", switchAt, n, want, maze)
+			}
+		}
+	}
+}
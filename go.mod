@@ -6,5 +6,5 @@ require (
 	github.com/fatih/color v1.10.0
 	github.com/gdamore/tcell/v2 v2.1.0
 	github.com/pkg/errors v0.9.1
-	github.com/stretchr/testify v1.7.0 // indirect
+	github.com/stretchr/testify v1.7.0
 )
@@ -0,0 +1,86 @@
+package horizon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultCallTimeout is how long a Call waits for a reply, unless Options say
+// otherwise.
+const DefaultCallTimeout = 10 * time.Second
+
+// ErrTimeout is passed to a ReplyFunc when no reply arrives in time.
+var ErrTimeout = errors.New("horizon: call timed out")
+
+// ReplyFunc handles the reply to a Call. err is set instead if the call timed
+// out.
+type ReplyFunc func(reply interface{}, err error)
+
+// call is a Call waiting for its reply.
+type call struct {
+	onReply ReplyFunc
+	timeout *Timer
+}
+
+// Reply sends val back to the object that made the Call which delivered e.
+func (o *object) Reply(e Event, val interface{}) {
+	if e.callID == 0 {
+		panic(fmt.Sprintf("horizon: %v replied to %q, which was not a Call", o, e.Name))
+	}
+	r := o.event(e.src, e.Name+"Reply", val)
+	r.replyTo = e.callID
+	o.eventLoop.events.push(r)
+}
+
+func (o *object) Call(dst Object, eventName string, param interface{}, onReply ReplyFunc) {
+	el := o.eventLoop
+	e := o.event(dst, eventName, param)
+	timeout := o.event(o, eventName+"Timeout", nil)
+
+	el.mu.Lock()
+	el.callSeq++
+	e.callID = el.callSeq
+	timeout.replyTo = e.callID
+	timeout.timedOut = true
+	c := &call{onReply: onReply}
+	if el.calls == nil {
+		el.calls = map[int]*call{}
+	}
+	el.calls[e.callID] = c
+	el.mu.Unlock()
+
+	// A call that its destination doesn't handle can only time out.
+	c.timeout = el.clock.schedule(timeout, el.callTimeout, 0)
+	if el.valid(e) {
+		el.events.push(e)
+	}
+}
+
+// reply delivers a reply or timeout event to the callback waiting for it.
+// Replies that arrive after their call timed out are dropped.
+func (el *eventLoop) reply(e Event) {
+	el.mu.Lock()
+	c, ok := el.calls[e.replyTo]
+	delete(el.calls, e.replyTo)
+	el.mu.Unlock()
+	if !ok {
+		return
+	}
+	if e.timedOut {
+		c.onReply(nil, errors.Wrapf(ErrTimeout, "%q after %v", e.Name, el.callTimeout))
+		return
+	}
+	c.timeout.Stop()
+	c.onReply(e.Arg, nil)
+}
+
+// CallID returns the correlation ID shared by a Call and its reply, or zero
+// for ordinary events.
+func (e Event) CallID() int {
+	if e.replyTo != 0 {
+		return e.replyTo
+	}
+	return e.callID
+}
@@ -0,0 +1,71 @@
+package horizon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCall(t *testing.T) {
+	el := NewEventLoopWith(Options{CallTimeout: time.Second})
+	var got []string
+	echo := NewObject("echo", func(self Object, e Event) {
+		switch e.Name {
+		case "echo":
+			self.Reply(e, e.Arg)
+		case "late":
+			// Answer after the caller gives up.
+			self.SendAfter(self, "answer", e, 2*time.Second)
+		case "answer":
+			self.Reply(e.Arg.(Event), "too late")
+		}
+	}, el)
+	NewObject("caller", func(self Object, e Event) {
+		if e.Name != "worldStart" {
+			return
+		}
+		// Replies are matched to calls, not to the order they were made in.
+		self.Call(echo, "late", nil, func(reply interface{}, err error) {
+			require.True(t, errors.Is(err, ErrTimeout), "%v", err)
+			require.Equal(t, time.Second, el.Now())
+			got = append(got, "timeout")
+		})
+		for _, s := range []string{"a", "b"} {
+			s := s
+			self.Call(echo, "echo", s, func(reply interface{}, err error) {
+				require.NoError(t, err)
+				require.Equal(t, s, reply)
+				got = append(got, reply.(string))
+			})
+		}
+	}, el)
+
+	s := el.Run(context.Background())
+	require.Equal(t, []string{"a", "b", "timeout"}, got)
+	require.Equal(t, Idle, s.Reason)
+	require.Equal(t, 2*time.Second, s.Time)
+}
+
+func TestCallID(t *testing.T) {
+	el := NewEventLoop()
+	var ids []int
+	callee := NewObject("callee", func(self Object, e Event) {
+		if e.Name == "ping" {
+			ids = append(ids, e.CallID())
+			self.Reply(e, nil)
+		}
+	}, el)
+	NewObject("caller", func(self Object, e Event) {
+		ids = append(ids, e.CallID())
+		if e.Name == "worldStart" {
+			self.Call(callee, "ping", nil, func(interface{}, error) {})
+		}
+	}, el)
+	el.Run(context.Background())
+	require.Equal(t, []int{0, 1}, ids)
+	require.Equal(t, "pingReply", el.(*eventLoop).log[len(el.(*eventLoop).log)-1].Name)
+	require.Equal(t, 1, el.(*eventLoop).log[len(el.(*eventLoop).log)-1].CallID())
+}
@@ -0,0 +1,104 @@
+package horizon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GroupByPrefix groups objects by the part of their ID before sep, so that
+// e.g. "cell[1,2]" and "cell[1,2]-probe-N" are drawn together.
+func GroupByPrefix(sep string) func(id string) string {
+	return func(id string) string {
+		if i := strings.Index(id, sep); i >= 0 {
+			return id[:i]
+		}
+		return id
+	}
+}
+
+// WriteDOT writes the object graph of el in Graphviz DOT format: a node for
+// each object, a solid edge for each wire labelled with its name, and a dashed
+// edge for each connection labelled with its event name. If group is not nil,
+// objects it maps to the same non-empty name are drawn in a cluster.
+func WriteDOT(w io.Writer, el EventLoop, group func(id string) string) error {
+	l := el.(*eventLoop)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph horizon {")
+	fmt.Fprintln(b, "\tnode [shape=box];")
+
+	// Clusters come first, in the order their first object was created.
+	var names []string
+	members := map[string][]*object{}
+	for _, o := range l.objects {
+		name := ""
+		if group != nil {
+			name = group(o.id)
+		}
+		if _, ok := members[name]; !ok {
+			names = append(names, name)
+		}
+		members[name] = append(members[name], o)
+	}
+	n := 0
+	for _, name := range names {
+		objs := members[name]
+		if name == "" || len(objs) < 2 {
+			for _, o := range objs {
+				fmt.Fprintf(b, "\t%s;\n", quote(o.id))
+			}
+			continue
+		}
+		fmt.Fprintf(b, "\tsubgraph cluster_%d {\n", n)
+		fmt.Fprintf(b, "\t\tlabel=%s;\n", quote(name))
+		for _, o := range objs {
+			fmt.Fprintf(b, "\t\t%s;\n", quote(o.id))
+		}
+		fmt.Fprintln(b, "\t}")
+		n++
+	}
+
+	for _, o := range l.objects {
+		wires := make([]string, 0, len(o.wires))
+		for name := range o.wires {
+			wires = append(wires, name)
+		}
+		sort.Strings(wires)
+		for _, name := range wires {
+			dst, _ := o.wires[name].(*object)
+			if dst == nil {
+				continue // Validate reports these
+			}
+			fmt.Fprintf(b, "\t%s -> %s [label=%s];\n", quote(o.id), quote(dst.id), quote(name))
+		}
+	}
+
+	subs := make([]subscription, 0, len(l.subs))
+	for key := range l.subs {
+		subs = append(subs, key)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].src.id != subs[j].src.id {
+			return subs[i].src.id < subs[j].src.id
+		}
+		return subs[i].name < subs[j].name
+	})
+	for _, key := range subs {
+		for _, dst := range l.subs[key] {
+			fmt.Fprintf(b, "\t%s -> %s [label=%s, style=dashed];\n", quote(key.src.id), quote(dst.id), quote(key.name))
+		}
+	}
+
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+// quote returns s as a DOT string.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
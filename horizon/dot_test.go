@@ -0,0 +1,43 @@
+package horizon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDOT(t *testing.T) {
+	el := NewEventLoop()
+	noop := func(Object, Event) {}
+	cell := NewObject("cell[0,0]", noop, el)
+	probe := NewObject("cell[0,0]-probe", noop, el)
+	ctrl := NewObject(`the "controller"`, noop, el)
+	cell.Wire(Wiring{"probe": probe})
+	probe.Wire(Wiring{"next": probe, "cell": cell, "broken": nil})
+	cell.Connect(ctrl, "finalRow")
+
+	var b strings.Builder
+	require.NoError(t, WriteDOT(&b, el, GroupByPrefix("-")))
+	require.Equal(t, `digraph horizon {
+	node [shape=box];
+	subgraph cluster_0 {
+		label="cell[0,0]";
+		"cell[0,0]";
+		"cell[0,0]-probe";
+	}
+	"the \"controller\"";
+	"cell[0,0]" -> "cell[0,0]-probe" [label="probe"];
+	"cell[0,0]-probe" -> "cell[0,0]" [label="cell"];
+	"cell[0,0]-probe" -> "cell[0,0]-probe" [label="next"];
+	"the \"controller\"" -> "cell[0,0]" [label="finalRow", style=dashed];
+}
+`, b.String())
+}
+
+func TestGroupByPrefix(t *testing.T) {
+	g := GroupByPrefix("-")
+	require.Equal(t, "cell[1,2]", g("cell[1,2]-probe-N"))
+	require.Equal(t, "cell[1,2]", g("cell[1,2]"))
+	require.Equal(t, "", g("-x"))
+}
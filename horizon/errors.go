@@ -0,0 +1,81 @@
+package horizon
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// ErrorPolicy says what Run does after a script panics.
+type ErrorPolicy int
+
+// The error policies.
+const (
+	// StopOnError makes Run return as soon as a script panics.
+	StopOnError ErrorPolicy = iota
+	// ContinueOnError drops the event that caused the panic and carries on.
+	ContinueOnError
+)
+
+// DefaultHistory is how many recent events a DispatchError carries, unless
+// Options say otherwise.
+const DefaultHistory = 10
+
+// DispatchError reports a panic while handling an event.
+type DispatchError struct {
+	Event    Event
+	Src, Dst string      // object IDs
+	Panic    interface{} // the value passed to panic
+	Stack    []byte      // where the panic happened
+	// History holds the events dispatched just before this one, oldest first.
+	History []Event
+}
+
+func (e *DispatchError) Error() string {
+	return fmt.Sprintf("horizon: %s panicked handling %q from %s: %v", e.Dst, e.Event.Name, e.Src, e.Panic)
+}
+
+// Details returns the error along with its event history and stack trace.
+func (e *DispatchError) Details() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, e.Error())
+	fmt.Fprintln(&b, "Recent events:")
+	for _, h := range e.History {
+		fmt.Fprintf(&b, "  %v\n", h)
+	}
+	fmt.Fprintf(&b, "  %v <- panicked here\n", e.Event)
+	b.Write(e.Stack)
+	return b.String()
+}
+
+// dispatch delivers e, turning a panic into a DispatchError.
+func (el *eventLoop) dispatch(e Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &DispatchError{
+				Event:   e,
+				Src:     e.src.id,
+				Dst:     e.dst.id,
+				Panic:   r,
+				Stack:   debug.Stack(),
+				History: el.history(),
+			}
+		}
+	}()
+	if e.replyTo != 0 {
+		el.reply(e)
+	} else {
+		e.dst.script(e.dst, e)
+	}
+	return nil
+}
+
+// history returns a copy of the events logged before the latest one.
+func (el *eventLoop) history() []Event {
+	end := len(el.log) - 1
+	start := end - el.historyLen
+	if start < 0 {
+		start = 0
+	}
+	return append([]Event(nil), el.log[start:end]...)
+}
@@ -0,0 +1,56 @@
+package horizon
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// buggy sends itself three ticks, and fails a type assertion on the second.
+func buggy(el EventLoop) {
+	NewObject("buggy", func(self Object, e Event) {
+		switch e.Name {
+		case "worldStart":
+			self.Send(self, "tick", 1)
+			self.Send(self, "tick", "two")
+			self.Send(self, "tick", 3)
+		case "tick":
+			_ = e.Arg.(int)
+		}
+	}, el)
+}
+
+func TestStopOnError(t *testing.T) {
+	el := NewEventLoopWith(Options{History: 2})
+	buggy(el)
+	s := el.Run(context.Background())
+	require.Equal(t, Failed, s.Reason)
+	require.Equal(t, 3, s.Events)
+	require.Len(t, s.Errors, 1)
+
+	err, ok := s.Errors[0].(*DispatchError)
+	require.True(t, ok, "%T", s.Errors[0])
+	require.Equal(t, "buggy", err.Src)
+	require.Equal(t, "buggy", err.Dst)
+	require.Equal(t, "two", err.Event.Arg)
+	require.Len(t, err.History, 2)
+	require.Equal(t, "worldStart", err.History[0].Name)
+	require.Equal(t, 1, err.History[1].Arg)
+	require.True(t, strings.HasPrefix(err.Error(), `horizon: buggy panicked handling "tick" from buggy: interface conversion`), err.Error())
+	require.Contains(t, err.Details(), "panicked here")
+
+	// The rest of the work is still there.
+	s = el.Run(context.Background())
+	require.Equal(t, Summary{Events: 1, Reason: Idle}, s)
+}
+
+func TestContinueOnError(t *testing.T) {
+	el := NewEventLoopWith(Options{OnError: ContinueOnError})
+	buggy(el)
+	s := el.Run(context.Background())
+	require.Equal(t, Idle, s.Reason)
+	require.Equal(t, 4, s.Events)
+	require.Len(t, s.Errors, 1)
+}
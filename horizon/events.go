@@ -4,63 +4,225 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
+	"sync"
+	"time"
 )
 
 // EventLoop is responsible for brokering events between all objects, using a
 // shared event queue.
 type EventLoop interface {
-	// Run runs the main event loop.
-	Run(context.Context)
-	Diagram() string // TODO: this belongs elsewhere
+	// Run runs the main event loop until there is no work left or ctx is done.
+	// Repeating timers count as work, so they must be stopped for Run to return
+	// by itself.
+	Run(context.Context) Summary
+	// Now returns the time on the event loop's clock, which starts at zero.
+	Now() time.Duration
+	// Validate checks that every object is wired up as its script requires,
+	// and returns a *WiringError for each problem. Call it before Run.
+	Validate() []error
 }
 
-// NewEventLoop returns an initialized EventLoop.
-func NewEventLoop() EventLoop {
-	return &eventLoop{
-		// TODO: NewObject writes to this channel before Run() is called, so this can deadlock.
-		events: make(chan Event, 50),
+// NewEventLoop returns an initialized EventLoop with a virtual clock.
+func NewEventLoop() EventLoop { return NewEventLoopWith(Options{}) }
+
+// Options configure an EventLoop.
+type Options struct {
+	// WallTime makes timers wait for real time to pass. By default the clock is
+	// virtual: it only moves when there is nothing left to do but wait for a
+	// timer, and then it jumps straight to that timer, so runs are fast and
+	// repeatable.
+	WallTime bool
+	// CallTimeout is how long a Call waits for a reply. Zero means
+	// DefaultCallTimeout.
+	CallTimeout time.Duration
+	// OnError says whether Run stops or continues after a script panics.
+	OnError ErrorPolicy
+	// History is how many recent events a DispatchError carries. Zero means
+	// DefaultHistory.
+	History int
+}
+
+// NewEventLoopWith returns an initialized EventLoop with the given options.
+func NewEventLoopWith(opts Options) EventLoop {
+	el := &eventLoop{
+		events:      newQueue(),
+		clock:       newClock(opts.WallTime),
+		callTimeout: opts.CallTimeout,
+		onError:     opts.OnError,
+		historyLen:  opts.History,
+	}
+	if el.callTimeout <= 0 {
+		el.callTimeout = DefaultCallTimeout
+	}
+	if el.historyLen <= 0 {
+		el.historyLen = DefaultHistory
 	}
+	return el
 }
 
 type eventLoop struct {
-	events chan Event
-	log    []Event // for diagrams
+	// Objects send events before Run is called, so the queue must not fill up.
+	// Events are only dispatched by Run, and Run returns once the queue is
+	// empty, so they should be sent before Run or by scripts.
+	events *queue
+	clock  *clock
+	log    []Event // for sequence diagrams
+
+	mu          sync.Mutex
+	objects     []*object // in creation order
+	subs        map[subscription][]*object
+	calls       map[int]*call
+	callSeq     int
+	callTimeout time.Duration
+	errs        []error // reported by the next Run
+	onError     ErrorPolicy
+	historyLen  int
+}
+
+// valid checks e against its destination's schema, and records an error if
+// it is not allowed.
+func (el *eventLoop) valid(e Event) bool {
+	err := e.dst.schema.check(e)
+	if err == nil {
+		return true
+	}
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	el.errs = append(el.errs, err)
+	return false
+}
+
+// subscription identifies the events that an object broadcasts by name.
+type subscription struct {
+	src  *object
+	name string
+}
+
+func (el *eventLoop) subscribe(src *object, name string, dst *object) {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	if el.subs == nil {
+		el.subs = map[subscription][]*object{}
+	}
+	key := subscription{src, name}
+	for _, o := range el.subs[key] {
+		if o == dst {
+			return
+		}
+	}
+	el.subs[key] = append(el.subs[key], dst)
 }
 
-func (el *eventLoop) Run(ctx context.Context) {
+// subscribers returns the objects connected to src's named events, in the
+// order they connected.
+func (el *eventLoop) subscribers(src *object, name string) []*object {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	return append([]*object(nil), el.subs[subscription{src, name}]...)
+}
+
+// StopReason says why Run returned.
+type StopReason string
+
+// The reasons Run can return.
+const (
+	// Idle means the queue ran dry with no timers pending, so no script can
+	// ever run again.
+	Idle StopReason = "idle"
+	// Canceled means the context was done before the work was.
+	Canceled StopReason = "canceled"
+	// Failed means a script panicked, and the policy is StopOnError.
+	Failed StopReason = "failed"
+)
+
+// Summary describes a call to Run.
+type Summary struct {
+	Events int           // the number of events dispatched
+	Time   time.Duration // the clock when Run returned
+	Reason StopReason
+	// Errors are the problems found since the last Run, such as events that
+	// did not match their destination's Schema, or a *DispatchError for each
+	// script that panicked.
+	Errors []error
+}
+
+// String returns a short description of the summary.
+func (s Summary) String() string {
+	str := fmt.Sprintf("%d events in %v, %s", s.Events, s.Time, s.Reason)
+	if len(s.Errors) > 0 {
+		str += fmt.Sprintf(", %d errors", len(s.Errors))
+	}
+	return str
+}
+
+func (el *eventLoop) Run(ctx context.Context) (s Summary) {
+	defer func() {
+		s.Time = el.clock.now()
+		el.mu.Lock()
+		s.Errors, el.errs = el.errs, nil
+		el.mu.Unlock()
+	}()
 	for {
-		select {
-		case e := <-el.events:
+		if ctx.Err() != nil {
+			s.Reason = Canceled
+			return s
+		}
+		for _, e := range el.clock.fire() {
+			el.events.push(e)
+		}
+		e, ok := el.events.pop()
+		if ok {
+			e.At = el.clock.now()
 			log.Println(e)
 			el.log = append(el.log, e)
-			e.dst.script(e.dst, e)
-		case <-ctx.Done():
-			return
+			s.Events++
+			if err := el.dispatch(e); err != nil {
+				el.mu.Lock()
+				el.errs = append(el.errs, err)
+				el.mu.Unlock()
+				if el.onError == StopOnError {
+					s.Reason = Failed
+					return s
+				}
+			}
+			continue
 		}
+		due, ok := el.clock.next()
+		if !ok {
+			s.Reason = Idle
+			return s
+		}
+		el.wait(ctx, due)
 	}
 }
 
-func (el *eventLoop) Diagram() string {
-	es := make([]string, 0, len(el.log))
-	objFmt := func(v interface{}) string {
-		if o, ok := v.(*object); ok {
-			return o.id
-		}
-		return fmt.Sprint(v)
+// wait blocks until the clock reaches due, or ctx is done.
+func (el *eventLoop) wait(ctx context.Context, due time.Duration) {
+	if !el.clock.wall {
+		el.clock.advance(due)
+		return
 	}
-	for _, e := range el.log {
-		es = append(es, fmt.Sprintf("%s -> %s: %s(%v)", objFmt(e.src), objFmt(e.dst), e.Name, objFmt(e.Arg)))
+	t := time.NewTimer(due - el.clock.now())
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
 	}
-	return strings.Join(es, "\n")
 }
 
+func (el *eventLoop) Now() time.Duration { return el.clock.now() }
+
 // Event mimics the data in a Horizon event
 type Event struct {
 	src, dst *object // src is for logging
 
 	Name string
 	Arg  interface{}
+	At   time.Duration // the clock when the event was dispatched
+
+	callID   int  // set on a Call
+	replyTo  int  // set on the reply or timeout of a Call
+	timedOut bool // set on the timeout of a Call
 }
 
 // String returns a debug representation of the event.
@@ -0,0 +1,54 @@
+package horizon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOrder(t *testing.T) {
+	// Far more objects than would fit in any fixed buffer.
+	const n = 1000
+	el := NewEventLoop()
+	var got []string
+	for i := 0; i < n; i++ {
+		id := fmt.Sprint(i)
+		NewObject(id, func(self Object, e Event) { got = append(got, id) }, el)
+	}
+	require.Equal(t, Summary{Events: n, Reason: Idle}, el.Run(context.Background()))
+	require.Len(t, got, n)
+	for i, id := range got {
+		require.Equal(t, fmt.Sprint(i), id)
+	}
+}
+
+func TestRunIdle(t *testing.T) {
+	el := NewEventLoop()
+	var got []string
+	NewObject("obj", func(self Object, e Event) {
+		got = append(got, e.Name)
+		switch e.Name {
+		case "worldStart":
+			self.Send(self, "ping", nil)
+		case "ping":
+			self.Send(self, "pong", nil)
+		}
+	}, el)
+	require.Equal(t, Summary{Events: 3, Reason: Idle}, el.Run(context.Background()))
+	require.Equal(t, []string{"worldStart", "ping", "pong"}, got)
+}
+
+func TestRunCanceled(t *testing.T) {
+	el := NewEventLoop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	NewObject("forever", func(self Object, e Event) {
+		if e.Name == "tick" {
+			cancel()
+		}
+		self.Send(self, "tick", nil)
+	}, el)
+	require.Equal(t, Summary{Events: 2, Reason: Canceled}, el.Run(ctx))
+}
@@ -2,6 +2,7 @@ package horizon
 
 import (
 	"fmt"
+	"time"
 )
 
 // Object represents a Horizon object, which can have a script attached or be
@@ -9,11 +10,35 @@ import (
 type Object interface {
 	// Send an event to another object.
 	Send(dst Object, eventName string, param interface{})
+	// Send an event to another object once delay has passed on the event
+	// loop's clock, like async.setTimeout.
+	SendAfter(dst Object, eventName string, param interface{}, delay time.Duration) *Timer
+	// Send an event to another object every interval until the timer is
+	// stopped, like async.setInterval.
+	SendEvery(dst Object, eventName string, param interface{}, interval time.Duration) *Timer
+	// Declare the events this object's script handles. Events sent to it that
+	// don't match are dropped and reported by Run. (Overwrites previous calls)
+	Handles(Schema)
+	// Declare the wires this object's script needs, for EventLoop.Validate.
+	// (Overwrites previous calls)
+	Requires(wires ...string)
 	// Set the object references for this object. (Overwrites previous calls)
 	Wire(Wiring)
 	// Return the object's wires
 	Wires() Wiring
-	// TODO: add listen and connect
+	// Broadcast an event to every object connected to this one, like
+	// sendCodeBlockEvent. An object with no script serves as a global channel.
+	Broadcast(eventName string, param interface{})
+	// Connect this object to the named events that src broadcasts, like
+	// connectCodeBlockEvent. Connecting more than once has no effect.
+	Connect(src Object, eventName string)
+	// Call sends an event to another object, and passes its Reply to onReply.
+	// If no reply arrives before the event loop's call timeout, onReply gets
+	// ErrTimeout instead.
+	Call(dst Object, eventName string, param interface{}, onReply ReplyFunc)
+	// Reply answers the Call that delivered e. The reply is named after the
+	// call, e.g. "getGroupNextReply".
+	Reply(e Event, val interface{})
 }
 
 // NewObject creates an object for the given script and connects it to the event loop.
@@ -23,10 +48,23 @@ func NewObject(id string, script Script, el EventLoop) Object {
 		script:    script,
 		eventLoop: el.(*eventLoop),
 	}
+	o.eventLoop.register(o)
 	o.Send(o, "worldStart", nil)
 	return o
 }
 
+// NewChannel creates an object with no behavior of its own, for objects to
+// connect to and broadcast on, like a global broadcast event.
+func NewChannel(id string, el EventLoop) Object {
+	o := &object{
+		id:        id,
+		script:    func(Object, Event) {},
+		eventLoop: el.(*eventLoop),
+	}
+	o.eventLoop.register(o)
+	return o
+}
+
 // Script represents a behavior attached to a Horizon object, which is a
 // collection of event handlers.
 type Script func(self Object, e Event)
@@ -38,12 +76,49 @@ type Wiring map[string]Object
 type object struct {
 	id        string
 	script    Script
+	schema    Schema
+	required  []string
 	wires     Wiring
 	eventLoop *eventLoop
 }
 
 func (o *object) Send(dst Object, eventName string, param interface{}) {
-	o.eventLoop.events <- Event{
+	if e := o.event(dst, eventName, param); o.eventLoop.valid(e) {
+		o.eventLoop.events.push(e)
+	}
+}
+
+func (o *object) SendAfter(dst Object, eventName string, param interface{}, delay time.Duration) *Timer {
+	e := o.event(dst, eventName, param)
+	if !o.eventLoop.valid(e) {
+		return &Timer{index: -1, clock: o.eventLoop.clock}
+	}
+	return o.eventLoop.clock.schedule(e, delay, 0)
+}
+
+func (o *object) SendEvery(dst Object, eventName string, param interface{}, interval time.Duration) *Timer {
+	if interval <= 0 {
+		panic(fmt.Sprintf("horizon: non-positive interval %v", interval))
+	}
+	e := o.event(dst, eventName, param)
+	if !o.eventLoop.valid(e) {
+		return &Timer{index: -1, clock: o.eventLoop.clock}
+	}
+	return o.eventLoop.clock.schedule(e, interval, interval)
+}
+
+func (o *object) Broadcast(eventName string, param interface{}) {
+	for _, dst := range o.eventLoop.subscribers(o, eventName) {
+		o.Send(dst, eventName, param)
+	}
+}
+
+func (o *object) Connect(src Object, eventName string) {
+	o.eventLoop.subscribe(src.(*object), eventName, o)
+}
+
+func (o *object) event(dst Object, eventName string, param interface{}) Event {
+	return Event{
 		src:  o,
 		dst:  dst.(*object),
 		Name: eventName,
@@ -51,8 +126,9 @@ func (o *object) Send(dst Object, eventName string, param interface{}) {
 	}
 }
 
-func (o *object) Wire(w Wiring) { o.wires = w }
-func (o *object) Wires() Wiring { return o.wires }
+func (o *object) Handles(s Schema) { o.schema = s }
+func (o *object) Wire(w Wiring)    { o.wires = w }
+func (o *object) Wires() Wiring    { return o.wires }
 
 // String returns the id of an object.
 func (o *object) String() string { return fmt.Sprintf("{%s}", o.id) }
@@ -0,0 +1,43 @@
+package horizon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcast(t *testing.T) {
+	el := NewEventLoop()
+	got := map[string][]string{}
+	listener := func(id string) Object {
+		return NewObject(id, func(self Object, e Event) {
+			if e.Name != "worldStart" {
+				got[id] = append(got[id], e.Name+":"+e.src.id)
+			}
+		}, el)
+	}
+	a, b, c := listener("a"), listener("b"), listener("c")
+	pub := NewObject("pub", func(self Object, e Event) {
+		if e.Name == "worldStart" {
+			self.Broadcast("news", nil)
+			self.Broadcast("weather", nil)
+		}
+	}, el)
+	global := NewChannel("global", el)
+
+	a.Connect(pub, "news")
+	a.Connect(pub, "news") // no duplicates
+	b.Connect(pub, "news")
+	b.Connect(pub, "weather")
+	c.Connect(global, "news")
+	a.Connect(global, "alarm")
+	global.Broadcast("news", nil)
+
+	el.Run(context.Background())
+	require.Equal(t, map[string][]string{
+		"a": {"news:pub"},
+		"b": {"news:pub", "weather:pub"},
+		"c": {"news:global"},
+	}, got)
+}
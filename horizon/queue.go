@@ -0,0 +1,31 @@
+package horizon
+
+import "sync"
+
+// queue is an unbounded FIFO of events. It is safe to push from any goroutine,
+// whether or not the event loop is running.
+type queue struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func newQueue() *queue { return &queue{} }
+
+func (q *queue) push(e Event) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.events = append(q.events, e)
+}
+
+// pop returns the oldest event, or false if there are none.
+func (q *queue) pop() (Event, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.events) == 0 {
+		return Event{}, false
+	}
+	e := q.events[0]
+	q.events[0] = Event{} // don't hold on to popped events
+	q.events = q.events[1:]
+	return e, true
+}
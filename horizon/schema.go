@@ -0,0 +1,77 @@
+package horizon
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Schema declares the events a script handles, and the type of argument each
+// one takes. A nil type means the event takes no argument. Every script
+// handles worldStart.
+type Schema map[string]reflect.Type
+
+// Argument types for use in a Schema. Use ArgType for anything else.
+var (
+	ObjectArg = reflect.TypeOf((*Object)(nil)).Elem()
+	AnyArg    = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// ArgType returns the type of v, for use in a Schema.
+func ArgType(v interface{}) reflect.Type { return reflect.TypeOf(v) }
+
+// SchemaError reports an event that its destination's Schema does not allow.
+// Such events are dropped instead of delivered.
+type SchemaError struct {
+	Src, Dst string // object IDs
+	Name     string
+	// Arg is the type of the argument that was sent, and Want is the type that
+	// the schema declares. Both are nil if the event is unhandled.
+	Arg, Want reflect.Type
+	Unhandled bool
+}
+
+func (e *SchemaError) Error() string {
+	if e.Unhandled {
+		return fmt.Sprintf("horizon: %s sent unhandled event %q to %s", e.Src, e.Name, e.Dst)
+	}
+	return fmt.Sprintf("horizon: %s sent %q to %s with %s, want %s", e.Src, e.Name, e.Dst, typeName(e.Arg), typeName(e.Want))
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "no argument"
+	}
+	return t.String()
+}
+
+// check returns an error if the schema does not allow e.
+func (s Schema) check(e Event) error {
+	if s == nil || e.Name == "worldStart" {
+		return nil
+	}
+	want, ok := s[e.Name]
+	if !ok {
+		return &SchemaError{Src: e.src.id, Dst: e.dst.id, Name: e.Name, Unhandled: true}
+	}
+	got := reflect.TypeOf(e.Arg)
+	if !assignable(got, want) {
+		return &SchemaError{Src: e.src.id, Dst: e.dst.id, Name: e.Name, Arg: got, Want: want}
+	}
+	return nil
+}
+
+// assignable reports whether an argument of type got is allowed where want is
+// declared. A nil argument is allowed for any type that can be nil.
+func assignable(got, want reflect.Type) bool {
+	if got == nil {
+		if want == nil {
+			return true
+		}
+		switch want.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Func, reflect.Map, reflect.Slice, reflect.Chan:
+			return true
+		}
+		return false
+	}
+	return want != nil && got.AssignableTo(want)
+}
@@ -0,0 +1,57 @@
+package horizon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	el := NewEventLoop()
+	var got []string
+	dst := NewObject("dst", func(self Object, e Event) {
+		if e.Name != "worldStart" {
+			got = append(got, e.Name)
+		}
+	}, el)
+	dst.Handles(Schema{
+		"none":   nil,
+		"count":  ArgType(0),
+		"object": ObjectArg,
+		"any":    AnyArg,
+	})
+	NewObject("src", func(self Object, e Event) {
+		if e.Name != "worldStart" {
+			return
+		}
+		self.Send(dst, "none", nil)
+		self.Send(dst, "none", 1)
+		self.Send(dst, "count", 1)
+		self.Send(dst, "count", "one")
+		self.Send(dst, "count", nil)
+		self.Send(dst, "object", self)
+		self.Send(dst, "object", nil)
+		self.Send(dst, "any", "anything")
+		self.Send(dst, "typo", nil)
+		self.SendAfter(dst, "typo", nil, 0)
+	}, el)
+
+	s := el.Run(context.Background())
+	require.Equal(t, []string{"none", "count", "object", "object", "any"}, got)
+	var msgs []string
+	for _, err := range s.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	require.Equal(t, []string{
+		`horizon: src sent "none" to dst with int, want no argument`,
+		`horizon: src sent "count" to dst with string, want int`,
+		`horizon: src sent "count" to dst with no argument, want int`,
+		`horizon: src sent unhandled event "typo" to dst`,
+		`horizon: src sent unhandled event "typo" to dst`,
+	}, msgs)
+	require.Equal(t, &SchemaError{Src: "src", Dst: "dst", Name: "count", Arg: ArgType(""), Want: ArgType(0)}, s.Errors[1])
+
+	// Errors are only reported once.
+	require.Empty(t, el.Run(context.Background()).Errors)
+}
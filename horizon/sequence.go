@@ -0,0 +1,142 @@
+package horizon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogFilter selects events from the log for a sequence diagram. The zero
+// value selects every event.
+type LogFilter struct {
+	// Names keeps only events with these names, if set.
+	Names []string
+	// IDs keeps only events sent to or from an object whose ID matches, if set.
+	IDs *regexp.Regexp
+	// From and To keep only events dispatched in [From, To). A zero To means
+	// there is no end.
+	From, To time.Duration
+}
+
+func (f LogFilter) match(e Event) bool {
+	if e.At < f.From || (f.To > 0 && e.At >= f.To) {
+		return false
+	}
+	if f.IDs != nil && !f.IDs.MatchString(e.src.id) && !f.IDs.MatchString(e.dst.id) {
+		return false
+	}
+	if len(f.Names) == 0 {
+		return true
+	}
+	for _, name := range f.Names {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteMermaid writes the events el has dispatched as a Mermaid
+// sequenceDiagram. Replies to a Call are drawn as dashed arrows.
+func WriteMermaid(w io.Writer, el EventLoop, f LogFilter) error {
+	return writeSequence(w, el, f, sequenceSyntax{
+		header:      "sequenceDiagram",
+		participant: func(alias, id string) string { return fmt.Sprintf("participant %s as %s", alias, mermaidEscape(id)) },
+		message: func(src, dst, text string, reply bool) string {
+			arrow := "->>"
+			if reply {
+				arrow = "-->>"
+			}
+			return fmt.Sprintf("%s%s%s: %s", src, arrow, dst, mermaidEscape(text))
+		},
+	})
+}
+
+// WritePlantUML writes the events el has dispatched as a PlantUML sequence
+// diagram. Replies to a Call are drawn as dashed arrows.
+func WritePlantUML(w io.Writer, el EventLoop, f LogFilter) error {
+	return writeSequence(w, el, f, sequenceSyntax{
+		header:      "@startuml",
+		footer:      "@enduml",
+		participant: func(alias, id string) string { return fmt.Sprintf("participant %s as %s", plantUMLQuote(id), alias) },
+		message: func(src, dst, text string, reply bool) string {
+			arrow := "->"
+			if reply {
+				arrow = "-->"
+			}
+			return fmt.Sprintf("%s %s %s : %s", src, arrow, dst, plantUMLEscape(text))
+		},
+	})
+}
+
+// sequenceSyntax is what differs between sequence diagram formats.
+type sequenceSyntax struct {
+	header, footer string
+	participant    func(alias, id string) string
+	message        func(src, dst, text string, reply bool) string
+}
+
+func writeSequence(w io.Writer, el EventLoop, f LogFilter, syn sequenceSyntax) error {
+	var events []Event
+	for _, e := range el.(*eventLoop).log {
+		if f.match(e) {
+			events = append(events, e)
+		}
+	}
+	// Object IDs are free text, so participants get plain aliases, declared in
+	// the order they first appear.
+	aliases := map[*object]string{}
+	var participants []*object
+	for _, e := range events {
+		for _, o := range []*object{e.src, e.dst} {
+			if _, ok := aliases[o]; !ok {
+				aliases[o] = fmt.Sprintf("p%d", len(participants))
+				participants = append(participants, o)
+			}
+		}
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, syn.header)
+	for _, o := range participants {
+		fmt.Fprintf(b, "    %s\n", syn.participant(aliases[o], o.id))
+	}
+	for _, e := range events {
+		text := fmt.Sprintf("%s(%s)", e.Name, argString(e.Arg))
+		fmt.Fprintf(b, "    %s\n", syn.message(aliases[e.src], aliases[e.dst], text, e.replyTo != 0))
+	}
+	if syn.footer != "" {
+		fmt.Fprintln(b, syn.footer)
+	}
+	return b.Flush()
+}
+
+// argString formats an event argument, naming objects by their ID.
+func argString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case *object:
+		return v.id
+	case Event:
+		return v.Name
+	}
+	return fmt.Sprint(v)
+}
+
+// mermaidEscape replaces the characters that end or comment out a Mermaid
+// statement with entity codes.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer("#", "#35;", ";", "#59;", "%", "#37;", "\n", "<br>").Replace(s)
+}
+
+func plantUMLQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "'", "\n", `\n`).Replace(s) + `"`
+}
+
+func plantUMLEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,87 @@
+package horizon
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pingPong returns an event loop that has run a short exchange between two
+// objects with awkward IDs.
+func pingPong() EventLoop {
+	el := NewEventLoop()
+	pong := NewObject("pong;#1", func(self Object, e Event) {
+		if e.Name == "ping" {
+			self.Reply(e, "50%")
+		}
+	}, el)
+	NewObject(`ping "a"`, func(self Object, e Event) {
+		switch e.Name {
+		case "worldStart":
+			self.SendAfter(self, "serve", pong, time.Second)
+		case "serve":
+			self.Call(e.Arg.(Object), "ping", nil, func(interface{}, error) {})
+		}
+	}, el)
+	el.Run(context.Background())
+	return el
+}
+
+func TestWriteMermaid(t *testing.T) {
+	el := pingPong()
+	var b strings.Builder
+	require.NoError(t, WriteMermaid(&b, el, LogFilter{}))
+	require.Equal(t, `sequenceDiagram
+    participant p0 as pong#59;#35;1
+    participant p1 as ping "a"
+    p0->>p0: worldStart()
+    p1->>p1: worldStart()
+    p1->>p1: serve(pong#59;#35;1)
+    p1->>p0: ping()
+    p0-->>p1: pingReply(50#37;)
+`, b.String())
+}
+
+func TestWritePlantUML(t *testing.T) {
+	el := pingPong()
+	var b strings.Builder
+	require.NoError(t, WritePlantUML(&b, el, LogFilter{From: time.Second}))
+	require.Equal(t, `@startuml
+    participant "ping 'a'" as p0
+    participant "pong;#1" as p1
+    p0 -> p0 : serve(pong;#1)
+    p0 -> p1 : ping()
+    p1 --> p0 : pingReply(50%)
+@enduml
+`, b.String())
+}
+
+func TestLogFilter(t *testing.T) {
+	el := pingPong()
+	for _, tt := range []struct {
+		name   string
+		filter LogFilter
+		want   []string
+	}{
+		{"all", LogFilter{}, []string{"worldStart", "worldStart", "serve", "ping", "pingReply"}},
+		{"names", LogFilter{Names: []string{"ping", "serve"}}, []string{"serve", "ping"}},
+		{"ids", LogFilter{IDs: regexp.MustCompile(`^pong`)}, []string{"worldStart", "ping", "pingReply"}},
+		{"before", LogFilter{To: time.Second}, []string{"worldStart", "worldStart"}},
+		{"after", LogFilter{From: time.Second}, []string{"serve", "ping", "pingReply"}},
+		{"none", LogFilter{Names: []string{"nope"}}, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range el.(*eventLoop).log {
+				if tt.filter.match(e) {
+					got = append(got, e.Name)
+				}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package horizon
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Timer is a pending SendAfter or SendEvery.
+type Timer struct {
+	event    Event
+	due      time.Duration
+	interval time.Duration // zero for one-shot timers
+	seq      int           // breaks ties in due, so timers fire in creation order
+	index    int           // in the heap, or -1 once the timer is done
+	clock    *clock
+}
+
+// Stop cancels the timer. It returns false if the timer already fired (for
+// SendAfter) or was already stopped.
+func (t *Timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	if t.index < 0 {
+		return false
+	}
+	heap.Remove(&t.clock.timers, t.index)
+	return true
+}
+
+// clock tracks time for an event loop, and the timers waiting on it.
+type clock struct {
+	mu      sync.Mutex
+	wall    bool
+	start   time.Time
+	virtual time.Duration
+	timers  timerHeap
+	seq     int
+}
+
+func newClock(wall bool) *clock {
+	return &clock{wall: wall, start: time.Now()}
+}
+
+// now returns the time since the event loop was created.
+func (c *clock) now() time.Duration {
+	if c.wall {
+		return time.Since(c.start)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.virtual
+}
+
+func (c *clock) schedule(e Event, delay, interval time.Duration) *Timer {
+	if delay < 0 {
+		delay = 0
+	}
+	t := &Timer{event: e, due: c.now() + delay, interval: interval, clock: c}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t.seq = c.seq
+	c.seq++
+	heap.Push(&c.timers, t)
+	return t
+}
+
+// next returns when the next timer is due, or false if there are none.
+func (c *clock) next() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.timers) == 0 {
+		return 0, false
+	}
+	return c.timers[0].due, true
+}
+
+// advance moves a virtual clock forward to d.
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > c.virtual {
+		c.virtual = d
+	}
+}
+
+// fire returns the events of every timer that is due, and reschedules the
+// repeating ones.
+func (c *clock) fire() []Event {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var es []Event
+	for len(c.timers) > 0 && c.timers[0].due <= now {
+		t := c.timers[0]
+		es = append(es, t.event)
+		if t.interval > 0 {
+			t.due += t.interval
+			t.seq = c.seq
+			c.seq++
+			heap.Fix(&c.timers, 0)
+		} else {
+			heap.Pop(&c.timers)
+		}
+	}
+	return es
+}
+
+// timerHeap is a min-heap of timers by due time, for use with container/heap.
+type timerHeap []*Timer
+
+func (h timerHeap) Len() int { return len(h) }
+func (h timerHeap) Less(i, j int) bool {
+	if h[i].due != h[j].due {
+		return h[i].due < h[j].due
+	}
+	return h[i].seq < h[j].seq
+}
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *timerHeap) Push(x interface{}) {
+	t := x.(*Timer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+func (h *timerHeap) Pop() interface{} {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	t.index = -1
+	return t
+}
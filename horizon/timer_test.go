@@ -0,0 +1,87 @@
+package horizon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendAfter(t *testing.T) {
+	el := NewEventLoop()
+	var got []string
+	NewObject("obj", func(self Object, e Event) {
+		got = append(got, fmt.Sprintf("%s@%v", e.Name, e.At))
+		if e.Name == "worldStart" {
+			self.SendAfter(self, "late", nil, 3*time.Second)
+			self.SendAfter(self, "early", nil, time.Second)
+			self.SendAfter(self, "tie", nil, time.Second)
+			self.SendAfter(self, "stopped", nil, 2*time.Second).Stop()
+			self.Send(self, "now", nil)
+		}
+	}, el)
+	s := el.Run(context.Background())
+	require.Equal(t, []string{"worldStart@0s", "now@0s", "early@1s", "tie@1s", "late@3s"}, got)
+	require.Equal(t, Summary{Events: 5, Time: 3 * time.Second, Reason: Idle}, s)
+}
+
+func TestSendEvery(t *testing.T) {
+	el := NewEventLoop()
+	var (
+		ticks []time.Duration
+		timer *Timer
+	)
+	NewObject("obj", func(self Object, e Event) {
+		switch e.Name {
+		case "worldStart":
+			timer = self.SendEvery(self, "tick", nil, 250*time.Millisecond)
+		case "tick":
+			ticks = append(ticks, e.At)
+			if len(ticks) == 4 {
+				require.True(t, timer.Stop())
+				require.False(t, timer.Stop())
+			}
+		}
+	}, el)
+	s := el.Run(context.Background())
+	require.Equal(t, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, 750 * time.Millisecond, time.Second}, ticks)
+	require.Equal(t, Idle, s.Reason)
+	require.Equal(t, time.Second, el.Now())
+}
+
+func TestSendEveryCanceled(t *testing.T) {
+	el := NewEventLoop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	NewObject("obj", func(self Object, e Event) {
+		switch e.Name {
+		case "worldStart":
+			self.SendEvery(self, "tick", nil, time.Hour)
+		case "tick":
+			if e.At >= 24*time.Hour {
+				cancel()
+			}
+		}
+	}, el)
+	s := el.Run(ctx)
+	require.Equal(t, Summary{Events: 25, Time: 24 * time.Hour, Reason: Canceled}, s)
+}
+
+func TestWallTime(t *testing.T) {
+	el := NewEventLoopWith(Options{WallTime: true})
+	var at time.Duration
+	NewObject("obj", func(self Object, e Event) {
+		switch e.Name {
+		case "worldStart":
+			self.SendAfter(self, "wake", nil, 20*time.Millisecond)
+		case "wake":
+			at = e.At
+		}
+	}, el)
+	start := time.Now()
+	el.Run(context.Background())
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+	require.GreaterOrEqual(t, int64(at), int64(20*time.Millisecond))
+}
@@ -0,0 +1,102 @@
+package horizon
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WiringProblem is the kind of mistake a WiringError reports.
+type WiringProblem string
+
+// The problems Validate looks for.
+const (
+	// MissingWire means a required wire was not set.
+	MissingWire WiringProblem = "missing"
+	// ExtraWire means a wire was set that the script does not require.
+	ExtraWire WiringProblem = "extra"
+	// NilWire means a wire was set to nil.
+	NilWire WiringProblem = "nil"
+	// Unreferenced means no other object is wired or connected to the object,
+	// so nothing can send it events once the world starts.
+	Unreferenced WiringProblem = "unreferenced"
+)
+
+// WiringError reports a mistake in how objects are wired together.
+type WiringError struct {
+	ID      string // the object with the problem
+	Wire    string // empty for Unreferenced
+	Problem WiringProblem
+}
+
+func (e *WiringError) Error() string {
+	if e.Wire == "" {
+		return fmt.Sprintf("horizon: %s is %s", e.ID, e.Problem)
+	}
+	return fmt.Sprintf("horizon: %s has %s wire %q", e.ID, e.Problem, e.Wire)
+}
+
+func (o *object) Requires(wires ...string) {
+	// Non-nil even when empty, so that every wire counts as extra.
+	o.required = append([]string{}, wires...)
+}
+
+func (el *eventLoop) register(o *object) {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	el.objects = append(el.objects, o)
+}
+
+// Validate checks the wiring of every object, in the order they were created.
+// Extra wires are only reported for objects that declared their requirements.
+func (el *eventLoop) Validate() []error {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	referenced := map[*object]bool{}
+	for key, subs := range el.subs {
+		for _, dst := range subs {
+			if dst != key.src {
+				referenced[dst] = true
+				referenced[key.src] = true
+			}
+		}
+	}
+	var errs []error
+	for _, o := range el.objects {
+		names := make([]string, 0, len(o.wires))
+		for name := range o.wires {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dst, _ := o.wires[name].(*object)
+			if dst == nil {
+				errs = append(errs, &WiringError{ID: o.id, Wire: name, Problem: NilWire})
+				continue
+			}
+			if dst != o {
+				referenced[dst] = true
+			}
+		}
+		required := map[string]bool{}
+		for _, name := range o.required {
+			required[name] = true
+			if _, ok := o.wires[name]; !ok {
+				errs = append(errs, &WiringError{ID: o.id, Wire: name, Problem: MissingWire})
+			}
+		}
+		if o.required == nil {
+			continue
+		}
+		for _, name := range names {
+			if !required[name] {
+				errs = append(errs, &WiringError{ID: o.id, Wire: name, Problem: ExtraWire})
+			}
+		}
+	}
+	for _, o := range el.objects {
+		if !referenced[o] {
+			errs = append(errs, &WiringError{ID: o.id, Problem: Unreferenced})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,40 @@
+package horizon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	el := NewEventLoop()
+	noop := func(Object, Event) {}
+	a := NewObject("a", noop, el)
+	b := NewObject("b", noop, el)
+	c := NewObject("c", noop, el)
+	lonely := NewObject("lonely", noop, el)
+	listener := NewObject("listener", noop, el)
+	channel := NewChannel("channel", el)
+
+	a.Requires("next", "prev")
+	a.Wire(Wiring{"next": b, "self": a, "nil": nil})
+	b.Requires("next")
+	b.Wire(Wiring{"next": c})
+	c.Wire(Wiring{"anything": a, "typed nil": (*object)(nil)})
+	lonely.Wire(Wiring{"next": a})
+	listener.Connect(channel, "news")
+
+	var msgs []string
+	for _, err := range el.Validate() {
+		msgs = append(msgs, err.Error())
+	}
+	require.Equal(t, []string{
+		`horizon: a has nil wire "nil"`,
+		`horizon: a has missing wire "prev"`,
+		`horizon: a has extra wire "nil"`,
+		`horizon: a has extra wire "self"`,
+		`horizon: c has nil wire "typed nil"`,
+		`horizon: lonely is unreferenced`,
+	}, msgs)
+	require.Equal(t, &WiringError{ID: "lonely", Problem: Unreferenced}, el.Validate()[5])
+}
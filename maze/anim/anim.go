@@ -0,0 +1,108 @@
+// Package anim records game runs as animated GIFs, without needing a terminal.
+package anim
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/misterikkit/automata/maze/game"
+	"github.com/misterikkit/automata/maze/region"
+)
+
+// Recorder collects one frame per generation of a game.
+type Recorder struct {
+	// Scale is the width and height of a cell, in pixels.
+	Scale int
+	// Delay is the time between frames, in 100ths of a second.
+	Delay int
+	// If Regions is set, the last frame shows each connected region of empty
+	// cells in its own color, like region.Map in the terminal.
+	Regions bool
+
+	frames []*image.Paletted
+	last   game.Game
+}
+
+// New returns a Recorder with reasonable defaults.
+func New() *Recorder {
+	return &Recorder{Scale: 4, Delay: 10}
+}
+
+// Add renders the next frame.
+func (r *Recorder) Add(g game.Game) {
+	r.frames = append(r.frames, r.render(g, nil))
+	r.last = g
+}
+
+// Len returns the number of frames so far.
+func (r *Recorder) Len() int { return len(r.frames) }
+
+// Encode writes all frames as an animated GIF, looping forever.
+func (r *Recorder) Encode(w io.Writer) error {
+	if len(r.frames) == 0 {
+		return errors.New("no frames to encode")
+	}
+	frames := r.frames
+	if r.Regions {
+		frames = append(frames[:len(frames)-1:len(frames)-1], r.render(r.last, region.Map(r.last).GetTag))
+	}
+	anim := &gif.GIF{}
+	for _, f := range frames {
+		anim.Image = append(anim.Image, f)
+		anim.Delay = append(anim.Delay, r.Delay)
+	}
+	// Hold the final state a little longer.
+	anim.Delay[len(anim.Delay)-1] = 10 * r.Delay
+	return errors.Wrap(gif.EncodeAll(w, anim), "unable to encode gif")
+}
+
+// render draws one frame. If tag is not nil, empty cells are colored by it.
+func (r *Recorder) render(g game.Game, tag func(row, col int) int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, g.Cols()*r.Scale, g.Rows()*r.Scale), palette)
+	for row := 0; row < g.Rows(); row++ {
+		for col := 0; col < g.Cols(); col++ {
+			idx := uint8(empty)
+			switch {
+			case bool(g.Get(row, col)):
+				idx = alive
+			case tag != nil && tag(row, col) >= 0:
+				idx = uint8(firstRegion + tag(row, col)%(len(palette)-firstRegion))
+			}
+			for y := 0; y < r.Scale; y++ {
+				for x := 0; x < r.Scale; x++ {
+					img.SetColorIndex(col*r.Scale+x, row*r.Scale+y, idx)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// Palette indices
+const (
+	empty = iota
+	alive
+	firstRegion
+)
+
+// palette roughly follows the terminal colors used for regions in tui.
+var palette = color.Palette{
+	color.RGBA{0xf0, 0xf0, 0xf0, 0xff}, // empty
+	color.RGBA{0x20, 0x20, 0x20, 0xff}, // alive
+	color.RGBA{0x8b, 0x00, 0x00, 0xff},
+	color.RGBA{0x00, 0x64, 0x00, 0xff},
+	color.RGBA{0xb8, 0x86, 0x0b, 0xff},
+	color.RGBA{0x00, 0x00, 0x8b, 0xff},
+	color.RGBA{0x8b, 0x00, 0x8b, 0xff},
+	color.RGBA{0x00, 0x8b, 0x8b, 0xff},
+	color.RGBA{0xff, 0x00, 0x00, 0xff},
+	color.RGBA{0x00, 0x80, 0x00, 0xff},
+	color.RGBA{0xff, 0xd7, 0x00, 0xff},
+	color.RGBA{0x00, 0x00, 0xff, 0xff},
+	color.RGBA{0xff, 0xb6, 0xc1, 0xff},
+	color.RGBA{0xe0, 0xff, 0xff, 0xff},
+}
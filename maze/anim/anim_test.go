@@ -0,0 +1,47 @@
+package anim
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/maze/game"
+	"github.com/misterikkit/automata/maze/gene"
+)
+
+func TestRecorder(t *testing.T) {
+	require := require.New(t)
+	g := game.New(10, 20)
+	// A wall splitting the board into two regions
+	for r := 0; r < g.Rows(); r++ {
+		g[r][10] = true
+	}
+	rec := New()
+	rec.Regions = true
+	rec.Add(g)
+	g = g.Next(gene.Clone())
+	rec.Add(g)
+	require.Equal(2, rec.Len())
+
+	var buf bytes.Buffer
+	require.NoError(rec.Encode(&buf))
+	out, err := gif.DecodeAll(&buf)
+	require.NoError(err)
+	require.Len(out.Image, 2)
+	require.Equal(20*rec.Scale, out.Image[0].Bounds().Dx())
+
+	first, last := out.Image[0], out.Image[1]
+	require.Equal(uint8(alive), first.ColorIndexAt(10*rec.Scale, 0))
+	require.Equal(uint8(empty), first.ColorIndexAt(0, 0))
+	// Only the last frame is colored, with a different color on each side.
+	west, east := last.ColorIndexAt(0, 0), last.ColorIndexAt(19*rec.Scale, 0)
+	require.GreaterOrEqual(west, uint8(firstRegion))
+	require.GreaterOrEqual(east, uint8(firstRegion))
+	require.NotEqual(west, east)
+}
+
+func TestRecorder_Empty(t *testing.T) {
+	require.Error(t, New().Encode(&bytes.Buffer{}))
+}
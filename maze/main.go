@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/misterikkit/automata/maze/anim"
 	"github.com/misterikkit/automata/maze/game"
 	"github.com/misterikkit/automata/maze/gene"
 	"github.com/misterikkit/automata/maze/region"
@@ -25,8 +27,13 @@ func main() {
 	h := flag.Int("h", 40, "height")
 	w := flag.Int("w", 100, "width")
 	merge := flag.String("merge", "", "specify a comma-separated list of genes to run them separately and take the intersection of their states")
+	gifFile := flag.String("gif", "", "if set, write each generation to this animated GIF. Requires -gens")
+	gifRegions := flag.Bool("regions", false, "with -gif, color the regions of the last frame")
 
 	flag.Parse()
+	if len(*gifFile) > 0 && (*genCount <= 0 || len(*merge) > 0) {
+		log.Fatal("-gif requires -gens, and does not work with -merge")
+	}
 	// Set random seed
 	if *seed == 0 {
 		*seed = time.Now().Unix()
@@ -59,8 +66,16 @@ func main() {
 	}
 
 	if len(*merge) == 0 {
-		if *genCount > 0 {
-			runAuto(&g, gn, *genCount)
+		if len(*gifFile) > 0 {
+			rec := anim.New()
+			rec.Regions = *gifRegions
+			runAuto(&g, gn, *genCount, rec)
+			if err := writeGIF(rec, *gifFile); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("Wrote %d frames to %s\n", rec.Len(), *gifFile)
+		} else if *genCount > 0 {
+			runAuto(&g, gn, *genCount, nil)
 		} else {
 			runInteractive(context.Background(), &g, gn)
 		}
@@ -72,7 +87,7 @@ func main() {
 		for _, geneStr := range geneStrs {
 			gn = gene.FromString(geneStr)
 			g = base.Next(gene.Clone())
-			runAuto(&g, gn, *genCount)
+			runAuto(&g, gn, *genCount, nil)
 
 			mapped := region.Map(g)
 			fmt.Printf("Intermediate result\n%v", tui.Fmt(mapped))
@@ -98,11 +113,31 @@ func main() {
 
 }
 
-func runAuto(g *game.Game, gn gene.Gene, n int) {
+// runAuto runs n generations. If rec is not nil, it records the initial state
+// and every generation.
+func runAuto(g *game.Game, gn gene.Gene, n int, rec *anim.Recorder) {
 	rule := gn.AsRule()
+	if rec != nil {
+		rec.Add(*g)
+	}
 	for i := 0; i < n; i++ {
 		*g = g.Next(rule)
+		if rec != nil {
+			rec.Add(*g)
+		}
+	}
+}
+
+func writeGIF(rec *anim.Recorder, name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := rec.Encode(f); err != nil {
+		f.Close()
+		return err
 	}
+	return f.Close()
 }
 
 func runInteractive(ctx context.Context, g *game.Game, gn gene.Gene) {
@@ -20,8 +20,8 @@ func TestMap(t *testing.T) {
 	for i := 0; i < 80; i++ {
 		g = g.Next(rule)
 	}
-	t.Logf("Game state:\n%v", tui.Fmt(g))
 	mapped := Map(g)
+	t.Logf("Game state:\n%v", tui.Fmt(mapped))
 	t.Logf("Game map:\n%v", mapped)
 }
 
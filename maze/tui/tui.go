@@ -2,12 +2,15 @@ package tui
 
 import (
 	"bytes"
+	"strings"
 
 	"github.com/fatih/color"
 	tcell "github.com/gdamore/tcell/v2"
 	"github.com/pkg/errors"
 
+	"github.com/misterikkit/automata/gen"
 	"github.com/misterikkit/automata/maze/game"
+	"github.com/misterikkit/automata/wall"
 )
 
 type Game interface {
@@ -28,8 +31,15 @@ const (
 	Left
 	Right
 	Enter
+	Up
+	Down
+	// Printable keys have their own events, from Rune.
+	runeEvents
 )
 
+// Rune returns the Event for pressing a printable key.
+func Rune(r rune) Event { return runeEvents + Event(r) }
+
 // TUI is a UI for the game.
 type TUI struct {
 	s tcell.Screen
@@ -64,6 +74,12 @@ func New(text string, handler func(Event)) (*TUI, error) {
 				t.h(Right)
 			case tcell.KeyEnter:
 				t.h(Enter)
+			case tcell.KeyUp:
+				t.h(Up)
+			case tcell.KeyDown:
+				t.h(Down)
+			case tcell.KeyRune:
+				t.h(Rune(ek.Rune()))
 			}
 		}
 	}(t)
@@ -91,6 +107,66 @@ func (t *TUI) DrawGame(g Game) {
 	t.s.Sync()
 }
 
+// SetText replaces the text shown below the grid, starting with the next draw.
+func (t *TUI) SetText(text string) { t.t = []rune(text) }
+
+// DrawMaze draws a walled maze, highlighting the current cell (if not nil) and
+// the frontier cells.
+func (t *TUI) DrawMaze(m *wall.Maze, current *gen.Cell, frontier []gen.Cell) {
+	highlight := map[gen.Cell]tcell.Style{}
+	for _, c := range frontier {
+		highlight[c] = tcell.StyleDefault.Background(tcell.ColorDarkBlue)
+	}
+	if current != nil {
+		highlight[*current] = tcell.StyleDefault.Background(tcell.ColorDarkRed)
+	}
+	t.DrawMazeStyled(m, func(c gen.Cell) (tcell.Style, bool) {
+		if s, ok := highlight[c]; ok {
+			return s, true
+		}
+		return tcell.StyleDefault, true
+	})
+}
+
+// CellStyle decides how to draw one cell of a maze, and whether it can be seen
+// at all.
+type CellStyle func(c gen.Cell) (style tcell.Style, visible bool)
+
+// DrawMazeStyled draws a walled maze with a style for each cell. Walls are
+// shown if they touch any visible cell.
+func (t *TUI) DrawMazeStyled(m *wall.Maze, style CellStyle) {
+	t.s.Clear()
+	visible := func(row, col int) bool {
+		if row < 0 || row >= m.Rows() || col < 0 || col >= m.Cols() {
+			return false
+		}
+		_, ok := style(gen.Cell{Row: row, Col: col})
+		return ok
+	}
+	// Reuse the text rendering. Cell (r, c) lands on line 2r+1, rune 2c+1, and
+	// the walls and corners around it are its neighbors.
+	lines := strings.Split(strings.TrimRight(m.String(), "\n"), "\n")
+	for y, line := range lines {
+		for x, r := range []rune(line) {
+			if y%2 == 1 && x%2 == 1 {
+				s, ok := style(gen.Cell{Row: y / 2, Col: x / 2})
+				if ok {
+					t.s.SetContent(x, y, r, nil, s)
+				}
+				continue
+			}
+			// Odd coordinates give the same cell twice, which is fine.
+			if visible((y-1)/2, (x-1)/2) || visible((y-1)/2, x/2) || visible(y/2, (x-1)/2) || visible(y/2, x/2) {
+				t.s.SetContent(x, y, r, nil, tcell.StyleDefault)
+			}
+		}
+	}
+	for i, r := range t.t {
+		t.s.SetContent(i, len(lines)+1, r, nil, tcell.StyleDefault)
+	}
+	t.s.Show()
+}
+
 func box(s tcell.Screen, x, y, w, h int) {
 	style := tcell.StyleDefault //.Foreground(tcell.ColorAliceBlue).Background(tcell.ColorOrchid)
 	for i := 0; i < w; i++ {
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/misterikkit/automata/gen"
+	"github.com/misterikkit/automata/trace"
+	"github.com/misterikkit/automata/wall"
+)
+
+// algoFlags lists the flags that only apply to some algorithms.
+var algoFlags = map[string][]string{
+	"policy":   {"growing-tree"},
+	"room":     {"division"},
+	"bias":     {"division"},
+	"corner":   {"binary-tree", "sidewinder"},
+	"p":        {"binary-tree", "sidewinder"},
+	"scan":     {"hunt-and-kill"},
+	"merge":    {"eller"},
+	"vertical": {"eller"},
+	"bands":    {"eller"},
+}
+
+func main() {
+	h := flag.Int("h", 10, "height")
+	w := flag.Int("w", 10, "width")
+	seed := flag.Int64("seed", 0, "random seed")
+	algo := flag.String("algo", "kruskal", fmt.Sprintf("generation algorithm. One of (%s)", strings.Join(gen.Names(), ", ")))
+	// Algorithm-specific options
+	policy := flag.String("policy", "newest:75,random:25", "for growing-tree, cell selection policy, e.g. newest, oldest, random, or newest:75,random:25")
+	room := flag.Int("room", 0, "for division, leave chambers up to this size undivided as open rooms")
+	bias := flag.Float64("bias", 0, "for division, between -1 (vertical cuts) and 1 (horizontal cuts)")
+	corner := flag.String("corner", "NE", "for binary-tree and sidewinder, the corner to bias toward")
+	prob := flag.Float64("p", 0.5, "for binary-tree, probability of carving vertically. For sidewinder, probability of closing a run")
+	scan := flag.String("scan", "row-major", "for hunt-and-kill, the hunt scan order. One of (row-major, serpentine, random)")
+	merge := flag.Float64("merge", 0.5, "for eller, probability of merging adjacent groups")
+	vertical := flag.String("vertical", "uniform", "for eller, how many cells of each group open south. One of (one, uniform, cell:P)")
+	bands := flag.String("bands", "", "for eller, optional per-row overrides as ROW,MERGE,VERTICAL;..., e.g. 0,0.9,one;20,0.3,cell:0.5")
+	chunks := flag.String("chunks", "", "if set, print chunks ROW,COL,ROWS,COLS of an endless maze. Each chunk is h x w")
+	tile := flag.Int("tile", 0, "if >0, split the maze into tiles of this size and generate them in parallel")
+	workers := flag.Int("workers", 0, "number of goroutines for -tile. 0 means one per CPU")
+	quiet := flag.Bool("q", false, "don't print the maze")
+	traceFile := flag.String("trace", "", "if set, record the generation to this JSONL file")
+	focus := flag.Bool("focus", false, "with -trace, also record the generator's current cell and frontier")
+	replay := flag.String("replay", "", "if set, load a trace from this file and print it instead of generating")
+	step := flag.Int("step", -1, "with -replay, print the maze after this step. -1 means the end")
+	flag.Parse()
+	if len(*replay) > 0 {
+		printStep(*replay, *step)
+		return
+	}
+	if *seed == 0 {
+		*seed = time.Now().Unix()
+	}
+	if *h < 1 || *w < 1 {
+		log.Fatalf("Bad size %dx%d: -h and -w must be at least 1", *h, *w)
+	}
+
+	hunts := -1
+	g, ok := gen.Lookup(*algo)
+	if !ok {
+		log.Fatalf("Unknown algorithm %q", *algo)
+	}
+	flag.Visit(func(f *flag.Flag) {
+		algos, ok := algoFlags[f.Name]
+		if !ok {
+			return
+		}
+		for _, a := range algos {
+			if a == *algo {
+				return
+			}
+		}
+		log.Fatalf("-%s only applies to %s", f.Name, strings.Join(algos, " and "))
+	})
+	switch *algo {
+	case "growing-tree":
+		sel, err := gen.ParseSelector(*policy)
+		if err != nil {
+			log.Fatal(err)
+		}
+		g = gen.GrowingTree(sel)
+	case "division":
+		g = gen.Division(gen.DivisionOptions{RoomSize: *room, Bias: *bias})
+	case "binary-tree", "sidewinder":
+		c, err := gen.ParseCorner(*corner)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *algo == "binary-tree" {
+			g = gen.BinaryTree(c, *prob)
+		} else {
+			g = gen.Sidewinder(c, *prob)
+		}
+	case "eller":
+		params, err := gen.ParseEllerOptions(*merge, *vertical, *bands)
+		if err != nil {
+			log.Fatal(err)
+		}
+		g = gen.Eller(params...)
+	case "hunt-and-kill":
+		o, err := gen.ParseScanOrder(*scan)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts := gen.HuntAndKillOptions{Scan: o}
+		// Tiles and chunks each run their own hunt, so there is no single count.
+		if *tile == 0 && len(*chunks) == 0 {
+			opts.Hunts = &hunts
+		}
+		g = gen.HuntAndKill(opts)
+	}
+	if *tile > 0 {
+		g = gen.Tiled(g, *tile, *tile, *workers)
+	}
+
+	start := time.Now()
+	var maze *wall.Maze
+	var tr *trace.Trace
+	if len(*chunks) > 0 {
+		var row, col, rows, cols int
+		if _, err := fmt.Sscanf(*chunks, "%d,%d,%d,%d", &row, &col, &rows, &cols); err != nil {
+			log.Fatalf("Bad -chunks %q: %v", *chunks, err)
+		}
+		world := gen.World{Seed: *seed, Rows: *h, Cols: *w, Generator: g}
+		maze = world.Region(row, col, rows, cols)
+	} else {
+		maze = wall.NewMaze(*h, *w)
+		if len(*traceFile) > 0 {
+			tr = trace.Record(maze, *focus)
+			tr.Seed = *seed
+		}
+		g(maze, rand.New(rand.NewSource(*seed)))
+	}
+	end := time.Now()
+
+	fmt.Printf("Generated %dx%d maze in %v\n", maze.Rows(), maze.Cols(), end.Sub(start))
+	if !*quiet {
+		fmt.Println(maze)
+	}
+	if hunts >= 0 {
+		fmt.Printf("Hunts: %d\n", hunts)
+	}
+	fmt.Printf("Seed: %v\n", *seed)
+
+	if tr != nil {
+		if err := save(tr, *traceFile); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Wrote %d steps to %s\n", len(tr.Steps), *traceFile)
+	}
+}
+
+func save(tr *trace.Trace, name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := tr.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printStep prints the maze from a trace file as it was after the given step,
+// marking the generator's current cell with @ and its frontier with dots.
+func printStep(name string, step int) {
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	tr, err := trace.Load(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if step < 0 || step > len(tr.Steps) {
+		step = len(tr.Steps)
+	}
+	maze := tr.Maze(step)
+	if step > 0 {
+		s := tr.Steps[step-1]
+		for _, c := range s.Frontier {
+			maze.Set(c.Row, c.Col, ".")
+		}
+		if s.Current != nil {
+			maze.Set(s.Current.Row, s.Current.Col, "@")
+		}
+	}
+	fmt.Println(maze)
+	fmt.Printf("Step %d of %d\n", step, len(tr.Steps))
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	tcell "github.com/gdamore/tcell/v2"
+
+	"github.com/misterikkit/automata/gen"
+	"github.com/misterikkit/automata/maze/tui"
+	"github.com/misterikkit/automata/solve"
+	"github.com/misterikkit/automata/wall"
+)
+
+func main() {
+	h := flag.Int("h", 10, "height")
+	w := flag.Int("w", 20, "width")
+	seed := flag.Int64("seed", 0, "random seed")
+	algo := flag.String("algo", "kruskal", fmt.Sprintf("generation algorithm. One of (%s)", strings.Join(gen.Names(), ", ")))
+	fog := flag.Int("fog", 0, "if >0, only show cells within this distance of the player, plus those already seen")
+	flag.Parse()
+	if *seed == 0 {
+		*seed = time.Now().Unix()
+	}
+
+	g, ok := gen.Lookup(*algo)
+	if !ok {
+		log.Fatalf("Unknown algorithm %q", *algo)
+	}
+	maze := wall.NewMaze(*h, *w)
+	g(maze, rand.New(rand.NewSource(*seed)))
+
+	gm := newGame(maze, *fog)
+	run(context.Background(), gm)
+	fmt.Println(gm.summary())
+	fmt.Printf("Seed: %v\n", *seed)
+}
+
+// game is the state of one playthrough. The player walks from the top-left
+// cell to the bottom-right one, leaving a trail of breadcrumbs in the maze.
+type game struct {
+	maze      *wall.Maze
+	pos, exit gen.Cell
+	moves     int
+	start     time.Time
+	end       time.Time // zero until the game is over
+	gaveUp    bool
+	fog       int
+	seen      map[gen.Cell]bool
+	solution  map[gen.Cell]bool
+}
+
+func newGame(m *wall.Maze, fog int) *game {
+	gm := &game{
+		maze:     m,
+		exit:     gen.Cell{Row: m.Rows() - 1, Col: m.Cols() - 1},
+		start:    time.Now(),
+		fog:      fog,
+		seen:     map[gen.Cell]bool{},
+		solution: map[gen.Cell]bool{},
+	}
+	m.Set(gm.exit.Row, gm.exit.Col, "E")
+	m.Set(gm.pos.Row, gm.pos.Col, "@")
+	gm.look()
+	return gm
+}
+
+func (gm *game) over() bool { return !gm.end.IsZero() }
+
+// move tries to walk through the wall on side d.
+func (gm *game) move(d wall.Direction) {
+	if gm.over() || !gm.maze.IsOpen(gm.pos.Row, gm.pos.Col, d) {
+		return
+	}
+	gm.maze.Set(gm.pos.Row, gm.pos.Col, ".")
+	gm.pos.Row, gm.pos.Col, _ = gm.maze.Neighbor(gm.pos.Row, gm.pos.Col, d)
+	gm.maze.Set(gm.pos.Row, gm.pos.Col, "@")
+	gm.moves++
+	gm.look()
+	if gm.pos == gm.exit {
+		gm.end = time.Now()
+	}
+}
+
+// giveUp ends the game and reveals the path from the entrance to the exit.
+func (gm *game) giveUp() {
+	if gm.over() {
+		return
+	}
+	gm.gaveUp = true
+	gm.end = time.Now()
+	for _, c := range solve.Path(gm.maze, gen.Cell{}, gm.exit) {
+		gm.solution[c] = true
+	}
+}
+
+// look marks the cells around the player as seen.
+func (gm *game) look() {
+	for r := gm.pos.Row - gm.fog; r <= gm.pos.Row+gm.fog; r++ {
+		for c := gm.pos.Col - gm.fog; c <= gm.pos.Col+gm.fog; c++ {
+			dr, dc := r-gm.pos.Row, c-gm.pos.Col
+			if dr*dr+dc*dc <= gm.fog*gm.fog {
+				gm.seen[gen.Cell{Row: r, Col: c}] = true
+			}
+		}
+	}
+}
+
+// style implements tui.CellStyle.
+func (gm *game) style(c gen.Cell) (tcell.Style, bool) {
+	visible := gm.fog <= 0 || gm.seen[c] || gm.gaveUp
+	switch {
+	case c == gm.pos:
+		return tcell.StyleDefault.Background(tcell.ColorDarkRed), true
+	case c == gm.exit:
+		return tcell.StyleDefault.Background(tcell.ColorDarkGreen), visible
+	case gm.solution[c]:
+		return tcell.StyleDefault.Background(tcell.ColorDarkBlue), true
+	}
+	return tcell.StyleDefault, visible
+}
+
+func (gm *game) elapsed() time.Duration {
+	end := gm.end
+	if !gm.over() {
+		end = time.Now()
+	}
+	return end.Sub(gm.start).Truncate(time.Second)
+}
+
+func (gm *game) summary() string {
+	switch {
+	case gm.gaveUp:
+		return fmt.Sprintf("Gave up after %d moves in %v", gm.moves, gm.elapsed())
+	case gm.over():
+		return fmt.Sprintf("Solved in %d moves and %v!", gm.moves, gm.elapsed())
+	}
+	return fmt.Sprintf("Moves: %d\tTime: %v", gm.moves, gm.elapsed())
+}
+
+func run(ctx context.Context, gm *game) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Initialize ui, and wire events
+	moveCh := make(chan wall.Direction, 1)
+	giveUpCh := make(chan struct{}, 1)
+	t, err := tui.New("", func(e tui.Event) {
+		switch e {
+		case tui.Escape:
+			cancel()
+		case tui.Up:
+			moveCh <- wall.North
+		case tui.Right:
+			moveCh <- wall.East
+		case tui.Down:
+			moveCh <- wall.South
+		case tui.Left:
+			moveCh <- wall.West
+		case tui.Rune('g'):
+			giveUpCh <- struct{}{}
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer t.Close()
+
+	draw := func() {
+		help := "Arrows:move\tg:give up\tEsc:exit"
+		if gm.over() {
+			help = "Esc:exit"
+		}
+		t.SetText(fmt.Sprintf("%s\t%s", gm.summary(), help))
+		t.DrawMazeStyled(gm.maze, gm.style)
+	}
+	draw()
+
+	// Redraw every second to keep the timer fresh.
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+loop:
+	for {
+		select {
+		case <-tick.C:
+			draw()
+
+		case d := <-moveCh:
+			gm.move(d)
+			draw()
+
+		case <-giveUpCh:
+			gm.giveUp()
+			draw()
+
+		case <-ctx.Done():
+			break loop
+		}
+	}
+}
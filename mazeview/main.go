@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/misterikkit/automata/gen"
+	"github.com/misterikkit/automata/maze/tui"
+	"github.com/misterikkit/automata/solve"
+	"github.com/misterikkit/automata/trace"
+	"github.com/misterikkit/automata/wall"
+)
+
+func main() {
+	h := flag.Int("h", 15, "height")
+	w := flag.Int("w", 30, "width")
+	seed := flag.Int64("seed", 0, "random seed")
+	algo := flag.String("algo", "kruskal", fmt.Sprintf("generation algorithm. One of (%s)", strings.Join(gen.Names(), ", ")))
+	solveIt := flag.Bool("solve", false, "after generating, solve from the top-left to the bottom-right corner and start playback there")
+	replay := flag.String("replay", "", "if set, play a trace file recorded by mazegen instead of generating")
+	flag.Parse()
+	if *seed == 0 {
+		*seed = time.Now().Unix()
+	}
+
+	var tr *trace.Trace
+	start := 0
+	if len(*replay) > 0 {
+		f, err := os.Open(*replay)
+		if err != nil {
+			log.Fatal(err)
+		}
+		tr, err = trace.Load(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		g, ok := gen.Lookup(*algo)
+		if !ok {
+			log.Fatalf("Unknown algorithm %q", *algo)
+		}
+		maze := wall.NewMaze(*h, *w)
+		tr = trace.Record(maze, true)
+		tr.Seed = *seed
+		g(maze, rand.New(rand.NewSource(*seed)))
+		if *solveIt {
+			start = len(tr.Steps)
+			solve.Solve(maze, gen.Cell{Row: 0, Col: 0}, gen.Cell{Row: *h - 1, Col: *w - 1})
+		}
+		maze.Observe(nil)
+	}
+
+	play(context.Background(), tr, start)
+	// A replayed trace only knows its seed if it was recorded with one.
+	if tr.Seed != 0 {
+		fmt.Printf("Seed: %v\n", tr.Seed)
+	}
+}
+
+// play shows the trace one step at a time, starting after the given step.
+func play(ctx context.Context, tr *trace.Trace, start int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Initialize ui, and wire events
+	pauseCh := make(chan struct{}, 1)
+	stepCh := make(chan int, 1)
+	speedCh := make(chan int, 1)
+	t, err := tui.New("", func(e tui.Event) {
+		switch e {
+		case tui.Escape:
+			cancel()
+		case tui.Enter:
+			pauseCh <- struct{}{}
+		case tui.Right:
+			stepCh <- 1
+		case tui.Left:
+			stepCh <- -1
+		case tui.Up:
+			speedCh <- 1
+		case tui.Down:
+			speedCh <- -1
+		}
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer t.Close()
+
+	maze := tr.Maze(start)
+	n := start
+	delay := 50 * time.Millisecond
+	paused := true
+	draw := func() {
+		var current *gen.Cell
+		var frontier []gen.Cell
+		if n > 0 {
+			current, frontier = tr.Steps[n-1].Current, tr.Steps[n-1].Frontier
+		}
+		state := "paused"
+		if !paused {
+			state = "playing"
+		}
+		t.SetText(fmt.Sprintf("Step %d/%d, %v/step, %s\tEnter:play/pause\t⬅➡:step\t⬆⬇:speed\tEsc:exit", n, len(tr.Steps), delay, state))
+		t.DrawMaze(maze, current, frontier)
+	}
+	forward := func() bool {
+		if n >= len(tr.Steps) {
+			return false
+		}
+		tr.Steps[n].Apply(maze)
+		n++
+		return true
+	}
+	back := func() {
+		if n > 0 {
+			// Walls can't be un-opened in general, so rebuild from scratch.
+			n--
+			maze = tr.Maze(n)
+		}
+	}
+	draw()
+
+	// Run the playback loop
+	tick := time.NewTicker(delay)
+	defer tick.Stop()
+loop:
+	for {
+		select {
+		case <-tick.C:
+			if paused {
+				break
+			}
+			if !forward() {
+				paused = true
+			}
+			draw()
+
+		case <-pauseCh:
+			paused = !paused
+			draw()
+
+		case d := <-stepCh:
+			paused = true
+			if d > 0 {
+				forward()
+			} else {
+				back()
+			}
+			draw()
+
+		case d := <-speedCh:
+			if d > 0 && delay > time.Millisecond {
+				delay /= 2
+			}
+			if d < 0 && delay < 2*time.Second {
+				delay *= 2
+			}
+			tick.Reset(delay)
+			draw()
+
+		case <-ctx.Done():
+			break loop
+		}
+	}
+}
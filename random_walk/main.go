@@ -8,14 +8,24 @@ import (
 	"log"
 	"math/rand"
 	"os"
+	"regexp"
+	"strings"
 	"time"
+
+	"github.com/misterikkit/automata/horizon"
 )
 
 func main() {
 	h := flag.Int("h", 5, "height")
 	w := flag.Int("w", 5, "width")
 	verbose := flag.Bool("v", false, "enable logging")
-	diagram := flag.String("diagram", "", "filename to write diagram")
+	diagram := flag.String("diagram", "", "filename to write a sequence diagram of the events")
+	format := flag.String("format", "mermaid", "sequence diagram format. One of (mermaid, plantuml)")
+	events := flag.String("events", "", "comma-separated event names to include in the diagram, default all")
+	ids := flag.String("ids", "", "regexp of object IDs to include in the diagram, default all")
+	from := flag.Duration("from", 0, "leave events before this time out of the diagram")
+	to := flag.Duration("to", 0, "leave events from this time on out of the diagram, if set")
+	dot := flag.String("dot", "", "filename to write the object graph in Graphviz DOT format")
 	flag.Parse()
 	if !*verbose {
 		log.SetOutput(io.Discard) // io.Discard is new in go1.16
@@ -27,17 +37,66 @@ func main() {
 
 	log.Printf("%-20v %-14q -> %-20v (%v)", "sender", "event", "recipient", "param")
 
+	if errs := m.Validate(); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+	filter := horizon.LogFilter{From: *from, To: *to}
+	if len(*events) > 0 {
+		filter.Names = strings.Split(*events, ",")
+	}
+	if len(*ids) > 0 {
+		re, err := regexp.Compile(*ids)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Bad -ids: %v\n", err)
+			os.Exit(1)
+		}
+		filter.IDs = re
+	}
+	if *format != "mermaid" && *format != "plantuml" {
+		fmt.Fprintf(os.Stderr, "Unknown diagram format %q\n", *format)
+		os.Exit(1)
+	}
+	if len(*dot) > 0 {
+		if err := writeFile(*dot, m.WriteDOT); err != nil {
+			fmt.Fprintf(os.Stderr, "DOT write fail: %v\n", err)
+		}
+	}
+
 	// start the engine
 	start := time.Now()
-	m.Run(context.Background())
+	summary := m.Run(context.Background())
 	end := time.Now()
 
-	fmt.Printf("Generated %dx%d maze in %v\n", *h, *w, end.Sub(start))
+	fmt.Printf("Generated %dx%d maze in %v (%v)\n", *h, *w, end.Sub(start), summary)
+	for _, err := range summary.Errors {
+		fmt.Println(err)
+		if de, ok := err.(*horizon.DispatchError); ok {
+			log.Print(de.Details())
+		}
+	}
 	fmt.Println(m)
 	if len(*diagram) > 0 {
-		err := os.WriteFile(*diagram, []byte(m.Diagram()), 0644)
-		if err != nil {
-			log.Printf("Diagram write fail: %f", err)
+		write := func(w io.Writer) error { return m.WriteMermaid(w, filter) }
+		if *format == "plantuml" {
+			write = func(w io.Writer) error { return m.WritePlantUML(w, filter) }
+		}
+		if err := writeFile(*diagram, write); err != nil {
+			fmt.Fprintf(os.Stderr, "Diagram write fail: %v\n", err)
 		}
 	}
 }
+
+func writeFile(filename string, write func(io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/misterikkit/automata/horizon"
 )
@@ -39,22 +40,36 @@ func NewMaze(rows, cols int) *Maze {
 	for r := range m.cells {
 		for c := range m.cells[r] {
 			name := fmt.Sprintf("cell[%d,%d]", r, c)
-			m.cells[r][c] = CellPartial{
+			p := CellPartial{
 				cell:   horizon.NewObject(name, Cell(), m.el),
 				probeN: horizon.NewObject(fmt.Sprintf("%s-probe-N", name), Probe(), m.el),
 				probeE: horizon.NewObject(fmt.Sprintf("%s-probe-E", name), Probe(), m.el),
 				probeS: horizon.NewObject(fmt.Sprintf("%s-probe-S", name), Probe(), m.el),
 				probeW: horizon.NewObject(fmt.Sprintf("%s-probe-W", name), Probe(), m.el),
 			}
+			p.cell.Handles(CellEvents)
+			p.cell.Requires(CellWires...)
+			for _, obj := range []horizon.Object{p.probeN, p.probeE, p.probeS, p.probeW} {
+				obj.Handles(ProbeEvents)
+				obj.Requires(ProbeWires...)
+			}
+			m.cells[r][c] = p
 			// Capture bool address in local var for the closure
 			openN := &m.cells[r][c].openN
 			openW := &m.cells[r][c].openW
-			m.cells[r][c].wallN = horizon.NewObject(fmt.Sprintf("%s-wall-N", name), Wall(func() { *openN = true }), m.el)
-			m.cells[r][c].wallW = horizon.NewObject(fmt.Sprintf("%s-wall-W", name), Wall(func() { *openW = true }), m.el)
+			// Walls on the north and west edges are the border instead.
+			if r > 0 {
+				m.cells[r][c].wallN = newWall(fmt.Sprintf("%s-wall-N", name), openN, m.el)
+			}
+			if c > 0 {
+				m.cells[r][c].wallW = newWall(fmt.Sprintf("%s-wall-W", name), openW, m.el)
+			}
 		}
 	}
 	// Time to wire them up!
 	m.border = horizon.NewObject("border", Terminator(), m.el)
+	m.border.Handles(TerminatorEvents)
+	m.border.Requires(TerminatorWires...)
 
 	for r := range m.cells {
 		for c := range m.cells[r] {
@@ -85,13 +100,28 @@ func NewMaze(rows, cols int) *Maze {
 	return m
 }
 
+func newWall(id string, open *bool, el horizon.EventLoop) horizon.Object {
+	w := horizon.NewObject(id, Wall(func() { *open = true }), el)
+	w.Handles(WallEvents)
+	w.Requires(WallWires...)
+	return w
+}
+
+// Validate checks the wiring of the maze before it runs.
+func (m *Maze) Validate() []error { return m.el.Validate() }
+
+// WriteDOT writes the maze's object graph in Graphviz DOT format, with each
+// cell drawn together with its probes and walls.
+func (m *Maze) WriteDOT(w io.Writer) error {
+	return horizon.WriteDOT(w, m.el, horizon.GroupByPrefix("-"))
+}
+
 // Run runs the maze generation algorithm, returning upon completion.
-func (m *Maze) Run(ctx context.Context) {
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-	init := horizon.NewObject("init", func(horizon.Object, horizon.Event) { cancel() }, m.el)
-	init.Send(m.cells[0][0].cell, "visit", init)
-	m.el.Run(ctx)
+func (m *Maze) Run(ctx context.Context) horizon.Summary {
+	// The first cell backtracks into the border, which ignores it, and then the
+	// event loop runs out of work.
+	m.border.Send(m.cells[0][0].cell, "visit", m.border)
+	return m.el.Run(ctx)
 }
 
 func (m *Maze) String() string {
@@ -216,7 +246,14 @@ func (m *Maze) cornerNW(row, col int) string {
 // |.|.|.|.|
 // +-+-+-+-+
 
-func (m *Maze) Diagram() string {
-	// TODO: this belongs elsewhere
-	return m.el.Diagram()
+// WriteMermaid writes the events of the last Run as a Mermaid sequence
+// diagram.
+func (m *Maze) WriteMermaid(w io.Writer, f horizon.LogFilter) error {
+	return horizon.WriteMermaid(w, m.el, f)
+}
+
+// WritePlantUML writes the events of the last Run as a PlantUML sequence
+// diagram.
+func (m *Maze) WritePlantUML(w io.Writer, f horizon.LogFilter) error {
+	return horizon.WritePlantUML(w, m.el, f)
 }
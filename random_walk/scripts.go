@@ -56,6 +56,17 @@ import (
 //     +----------+ PROBE +<---------+                          +----------+ PROBE +<---------+
 //                +-------+                                                +-------+
 
+// CellEvents are the events handled by Cell.
+var CellEvents = horizon.Schema{
+	"visit":     horizon.ObjectArg,
+	"check":     horizon.ObjectArg,
+	"deadEnd":   nil,
+	"backTrack": nil,
+}
+
+// CellWires are the wires required by Cell.
+var CellWires = []string{"probe"}
+
 // Cell implements the behavior for one empty space in the maze. It expects a
 // Probe for each neighboring cell, arranged in a linked list cycle. Only one
 // Probe needs to be wired into the Cell.
@@ -89,6 +100,19 @@ func Cell() horizon.Script {
 	}
 }
 
+// ProbeEvents are the events handled by Probe.
+var ProbeEvents = horizon.Schema{
+	"visitRand":   horizon.ArgType(0),
+	"tryVisit":    horizon.ObjectArg,
+	"checkResult": horizon.ArgType(false),
+	"check":       horizon.ObjectArg,
+	"visit":       horizon.ObjectArg,
+	"backTrack":   nil,
+}
+
+// ProbeWires are the wires required by Probe.
+var ProbeWires = []string{"cell", "next", "wall"}
+
 // Probe does most of the work in this algorithm, and represents one neighbor of
 // one cell. There is another Probe in the neighboring cell which represents the
 // cell of this Probe. These two probes pass messages to each other through the
@@ -145,6 +169,15 @@ func Probe() horizon.Script {
 	}
 }
 
+// WallEvents are the events handled by Wall.
+var WallEvents = horizon.Schema{
+	"visit": horizon.ObjectArg,
+	"check": horizon.ObjectArg,
+}
+
+// WallWires are the wires required by Wall.
+var WallWires = []string{"probe1", "probe2"}
+
 // Wall represents a barrier between cells. It passes messages between two cells
 // via their Probes, and is also the primary output of the algorithm!
 func Wall(onOpen func()) horizon.Script {
@@ -167,6 +200,16 @@ func Wall(onOpen func()) horizon.Script {
 	}
 }
 
+// TerminatorEvents are the events handled by Terminator. It ignores the
+// backTrack from the first cell, which ends the algorithm.
+var TerminatorEvents = horizon.Schema{
+	"check":     horizon.ObjectArg,
+	"backTrack": nil,
+}
+
+// TerminatorWires are the wires required by Terminator, which is none.
+var TerminatorWires = []string{}
+
 // Terminator returns a dummy "visited" script so that we don't traverse it.
 func Terminator() horizon.Script {
 	return func(self horizon.Object, e horizon.Event) {
@@ -0,0 +1,79 @@
+// Package solve finds paths through a wall.Maze.
+package solve
+
+import (
+	"github.com/misterikkit/automata/gen"
+	"github.com/misterikkit/automata/wall"
+)
+
+// Path returns the shortest path between two cells, including both ends, or
+// nil if there is none.
+func Path(m *wall.Maze, from, to gen.Cell) []gen.Cell {
+	return search(m, from, to, false)
+}
+
+// Solve finds the shortest path like Path, but draws its work onto the maze as
+// it goes: each explored cell is Set to "." and then the path to "*". It also
+// reports its progress to a gen.Focuser, so recording the maze with the trace
+// package turns a solve into an animation.
+func Solve(m *wall.Maze, from, to gen.Cell) []gen.Cell {
+	return search(m, from, to, true)
+}
+
+// search is a breadth-first search that can optionally draw on the maze.
+func search(m *wall.Maze, from, to gen.Cell, draw bool) []gen.Cell {
+	if !inside(m, from) || !inside(m, to) {
+		return nil
+	}
+	f, _ := m.Observer().(gen.Focuser)
+	// prev records how each cell was reached, which doubles as the visited set.
+	prev := map[gen.Cell]gen.Cell{from: from}
+	queue := []gen.Cell{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if draw {
+			if f != nil {
+				f.Focus(cur, queue)
+			}
+			m.Set(cur.Row, cur.Col, ".")
+		}
+		if cur == to {
+			break
+		}
+		for _, d := range wall.Directions {
+			r, c, ok := m.Neighbor(cur.Row, cur.Col, d)
+			next := gen.Cell{Row: r, Col: c}
+			if _, seen := prev[next]; !ok || seen || !m.IsOpen(cur.Row, cur.Col, d) {
+				continue
+			}
+			prev[next] = cur
+			queue = append(queue, next)
+		}
+	}
+	if _, ok := prev[to]; !ok {
+		return nil
+	}
+
+	var path []gen.Cell
+	for cur := to; cur != from; cur = prev[cur] {
+		path = append(path, cur)
+	}
+	path = append(path, from)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	if draw {
+		for _, c := range path {
+			if f != nil {
+				f.Focus(c, nil)
+			}
+			m.Set(c.Row, c.Col, "*")
+		}
+	}
+	return path
+}
+
+func inside(m *wall.Maze, c gen.Cell) bool {
+	return c.Row >= 0 && c.Row < m.Rows() && c.Col >= 0 && c.Col < m.Cols()
+}
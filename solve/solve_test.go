@@ -0,0 +1,55 @@
+package solve
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/gen"
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestPath(t *testing.T) {
+	// ┌─────┐
+	// │     │
+	// ├───╴ │
+	// │     │
+	// └─────┘
+	m := wall.NewMaze(2, 3)
+	m.Open(0, 0, wall.East)
+	m.Open(0, 1, wall.East)
+	m.Open(0, 2, wall.South)
+	m.Open(1, 2, wall.West)
+	m.Open(1, 1, wall.West)
+	tests := []struct {
+		name     string
+		from, to gen.Cell
+		want     []gen.Cell
+	}{
+		{"same cell", gen.Cell{Row: 1, Col: 1}, gen.Cell{Row: 1, Col: 1}, []gen.Cell{{Row: 1, Col: 1}}},
+		{"neighbors", gen.Cell{Row: 0, Col: 0}, gen.Cell{Row: 0, Col: 1}, []gen.Cell{{Row: 0, Col: 0}, {Row: 0, Col: 1}}},
+		{"around the wall", gen.Cell{Row: 0, Col: 0}, gen.Cell{Row: 1, Col: 0}, []gen.Cell{
+			{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 0, Col: 2}, {Row: 1, Col: 2}, {Row: 1, Col: 1}, {Row: 1, Col: 0},
+		}},
+		{"outside", gen.Cell{Row: 0, Col: 0}, gen.Cell{Row: 5, Col: 0}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Path(m, tt.from, tt.to))
+		})
+	}
+	m.Close(0, 1, wall.East)
+	require.Nil(t, Path(m, gen.Cell{Row: 0, Col: 0}, gen.Cell{Row: 1, Col: 0}), "unreachable")
+}
+
+func TestSolve(t *testing.T) {
+	m := wall.NewMaze(10, 10)
+	gen.Kruskal()(m, rand.New(rand.NewSource(1)))
+	from, to := gen.Cell{Row: 0, Col: 0}, gen.Cell{Row: 9, Col: 9}
+	want := Path(m, from, to)
+	require.NotEmpty(t, want)
+	require.Equal(t, want, Solve(m, from, to))
+	require.Equal(t, len(want), strings.Count(m.String(), "*"))
+}
@@ -0,0 +1,186 @@
+// Package trace records the changes made to a wall.Maze, so that its
+// generation can be saved, replayed, and drawn one step at a time.
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/misterikkit/automata/gen"
+	"github.com/misterikkit/automata/wall"
+)
+
+// Op is the kind of change made in a Step.
+type Op string
+
+// The ops that a trace can record, one for each wall.Observer method.
+const (
+	Open  Op = "open"
+	Close Op = "close"
+	Set   Op = "set"
+)
+
+// Step is one change to a maze.
+type Step struct {
+	// Steps are numbered from 1, so that Maze(n) shows the maze after step n.
+	N   int            `json:"step"`
+	Op  Op             `json:"op"`
+	Row int            `json:"row"`
+	Col int            `json:"col"`
+	Dir wall.Direction `json:"dir,omitempty"`
+	Val string         `json:"val,omitempty"`
+	// What the generator was working on, if the trace was recorded with focus
+	// and the generator reported it.
+	Current  *gen.Cell  `json:"current,omitempty"`
+	Frontier []gen.Cell `json:"frontier,omitempty"`
+}
+
+// Trace is a recording of every change made to a maze.
+type Trace struct {
+	Rows int `json:"rows"`
+	Cols int `json:"cols"`
+	// Seed is the random seed the maze was generated with, or 0 if unknown.
+	Seed  int64  `json:"seed,omitempty"`
+	Steps []Step `json:"-"`
+}
+
+// Record starts recording every change to m into a new Trace, replacing any
+// other observer. If focus is set, steps also carry the generator's current
+// cell and frontier. Call m.Observe(nil) to stop recording.
+func Record(m *wall.Maze, focus bool) *Trace {
+	t := &Trace{Rows: m.Rows(), Cols: m.Cols()}
+	m.Observe(&recorder{tape: &tape{t: t, focus: focus}})
+	return t
+}
+
+// tape is the Trace shared by all the recorders of a maze. It is safe for
+// concurrent use, since tiled generators work in parallel.
+type tape struct {
+	mu    sync.Mutex
+	t     *Trace
+	focus bool
+}
+
+// recorder is a wall.Observer and gen.Focuser that appends to a tape. Each Sub
+// view gets its own recorder, so that a step carries the focus reported in the
+// view that made it, even while other views are generating.
+type recorder struct {
+	*tape
+	current  *gen.Cell
+	frontier []gen.Cell
+}
+
+func (r *recorder) add(s Step) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.N = len(r.t.Steps) + 1
+	if r.focus {
+		s.Current, s.Frontier = r.current, r.frontier
+	}
+	r.t.Steps = append(r.t.Steps, s)
+}
+
+func (r *recorder) Opened(row, col int, d wall.Direction) {
+	r.add(Step{Op: Open, Row: row, Col: col, Dir: d})
+}
+
+func (r *recorder) Closed(row, col int, d wall.Direction) {
+	r.add(Step{Op: Close, Row: row, Col: col, Dir: d})
+}
+
+func (r *recorder) Set(row, col int, val string) {
+	r.add(Step{Op: Set, Row: row, Col: col, Val: val})
+}
+
+func (r *recorder) View(row, col, rows, cols int) wall.Observer {
+	return &recorder{tape: r.tape}
+}
+
+func (r *recorder) Focus(current gen.Cell, frontier []gen.Cell) {
+	if !r.focus {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = &current
+	r.frontier = nil
+	if len(frontier) > 0 {
+		r.frontier = append([]gen.Cell(nil), frontier...)
+	}
+}
+
+// Replay applies the first n steps to m. Use a negative n for all steps.
+func (t *Trace) Replay(m *wall.Maze, n int) {
+	if n < 0 || n > len(t.Steps) {
+		n = len(t.Steps)
+	}
+	for _, s := range t.Steps[:n] {
+		s.Apply(m)
+	}
+}
+
+// Apply makes the step's change to m.
+func (s Step) Apply(m *wall.Maze) {
+	switch s.Op {
+	case Open:
+		m.Open(s.Row, s.Col, s.Dir)
+	case Close:
+		m.Close(s.Row, s.Col, s.Dir)
+	case Set:
+		m.Set(s.Row, s.Col, s.Val)
+	}
+}
+
+// Maze returns a fresh maze as it was after step n. Step 0 is the empty maze,
+// and a negative n means the finished maze.
+func (t *Trace) Maze(n int) *wall.Maze {
+	m := wall.NewMaze(t.Rows, t.Cols)
+	t.Replay(m, n)
+	return m
+}
+
+// Save writes the trace as JSON lines: a header with the maze size, then one
+// line per step.
+func (t *Trace) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(t); err != nil {
+		return errors.Wrap(err, "unable to write trace header")
+	}
+	for _, s := range t.Steps {
+		if err := enc.Encode(s); err != nil {
+			return errors.Wrapf(err, "unable to write step %d", s.N)
+		}
+	}
+	return nil
+}
+
+// Load reads a trace written by Save.
+func Load(r io.Reader) (*Trace, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<24) // frontiers can make for long lines
+	t := &Trace{}
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, errors.Wrap(err, "unable to read trace header")
+		}
+		return nil, errors.New("empty trace")
+	}
+	if err := json.Unmarshal(sc.Bytes(), t); err != nil {
+		return nil, errors.Wrap(err, "bad trace header")
+	}
+	for sc.Scan() {
+		var s Step
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			return nil, errors.Wrapf(err, "bad step on line %d", len(t.Steps)+2)
+		}
+		t.Steps = append(t.Steps, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to read trace")
+	}
+	return t, nil
+}
@@ -0,0 +1,103 @@
+package trace
+
+import (
+	"bytes"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/misterikkit/automata/gen"
+	"github.com/misterikkit/automata/wall"
+)
+
+func TestRecordReplay(t *testing.T) {
+	require := require.New(t)
+	m := wall.NewMaze(6, 8)
+	tr := Record(m, true)
+	tr.Seed = 1
+	gen.Prim(gen.PrimOptions{})(m, rand.New(rand.NewSource(1)))
+	m.Set(0, 0, "S")
+	m.Observe(nil)
+	m.Set(5, 7, "E") // not recorded
+
+	require.Len(tr.Steps, 6*8-1+1)
+	require.Equal(1, tr.Steps[0].N)
+	require.NotNil(tr.Steps[0].Current)
+	require.NotEmpty(tr.Steps[0].Frontier)
+	require.Equal(Set, tr.Steps[len(tr.Steps)-1].Op)
+
+	var buf bytes.Buffer
+	require.NoError(tr.Save(&buf))
+	loaded, err := Load(&buf)
+	require.NoError(err)
+	require.Equal(tr, loaded)
+
+	m.Set(5, 7, "")
+	require.Equal(m.String(), loaded.Maze(-1).String())
+	require.Equal(wall.NewMaze(6, 8).String(), loaded.Maze(0).String())
+	// Intermediate steps have exactly that many openings.
+	partial := loaded.Maze(10)
+	open := 0
+	for r := 0; r < partial.Rows(); r++ {
+		for c := 0; c < partial.Cols(); c++ {
+			if partial.IsOpen(r, c, wall.East) {
+				open++
+			}
+			if partial.IsOpen(r, c, wall.South) {
+				open++
+			}
+		}
+	}
+	require.Equal(10, open)
+}
+
+func TestRecord_Division(t *testing.T) {
+	// Division closes walls, and Tiled works on sub-views in parallel. Both
+	// must still replay to the same maze.
+	for _, g := range []gen.Generator{
+		gen.Division(gen.DivisionOptions{RoomSize: 3}),
+		gen.Tiled(gen.Kruskal(), 4, 4, 4),
+	} {
+		m := wall.NewMaze(12, 12)
+		tr := Record(m, false)
+		g(m, rand.New(rand.NewSource(1)))
+		require.Equal(t, m.String(), tr.Maze(-1).String())
+		require.Nil(t, tr.Steps[0].Current)
+	}
+}
+
+func TestRecord_TiledFocus(t *testing.T) {
+	// Tiles only interleave if they really run in parallel.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	const size, tile = 60, 6
+	m := wall.NewMaze(size, size)
+	tr := Record(m, true)
+	gen.Tiled(gen.Prim(gen.PrimOptions{}), tile, tile, 8)(m, rand.New(rand.NewSource(1)))
+	tileOf := func(row, col int) gen.Cell { return gen.Cell{Row: row / tile, Col: col / tile} }
+	for _, s := range tr.Steps {
+		r, c, _ := m.Neighbor(s.Row, s.Col, s.Dir)
+		if tileOf(r, c) != tileOf(s.Row, s.Col) {
+			// Stitches between tiles are opened, and focused, by Tiled itself.
+			require.Equal(t, &gen.Cell{Row: s.Row, Col: s.Col}, s.Current, "step %d", s.N)
+			continue
+		}
+		require.NotNil(t, s.Current, "step %d", s.N)
+		require.Equal(t, tileOf(s.Row, s.Col), tileOf(s.Current.Row, s.Current.Col), "step %d", s.N)
+		for _, f := range s.Frontier {
+			require.Equal(t, tileOf(s.Row, s.Col), tileOf(f.Row, f.Col), "step %d", s.N)
+		}
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"not json\n",
+		`{"rows":2,"cols":2}` + "\n" + `{"step":1,"op":"open","dir":"Q"}` + "\n",
+	} {
+		_, err := Load(bytes.NewBufferString(in))
+		require.Error(t, err, "input %q", in)
+	}
+}
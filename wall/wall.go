@@ -1,6 +1,9 @@
 package wall
 
-import "bytes"
+import (
+	"bytes"
+	"fmt"
+)
 
 // Direction is a compass direction
 type Direction int
@@ -13,12 +16,76 @@ const (
 	West
 )
 
+// String returns the compass letter(s) for a direction.
+func (d Direction) String() string {
+	var b bytes.Buffer
+	for i, name := range "NESW" {
+		if d&Directions[i] > 0 {
+			b.WriteRune(name)
+		}
+	}
+	return b.String()
+}
+
+// MarshalText encodes a direction as compass letters, e.g. "N" or "NE".
+func (d Direction) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// UnmarshalText decodes compass letters into a direction.
+func (d *Direction) UnmarshalText(text []byte) error {
+	*d = 0
+	for _, r := range string(text) {
+		switch r {
+		case 'N':
+			*d |= North
+		case 'E':
+			*d |= East
+		case 'S':
+			*d |= South
+		case 'W':
+			*d |= West
+		default:
+			return fmt.Errorf("bad direction %q", text)
+		}
+	}
+	return nil
+}
+
+// Directions lists each single direction, in clockwise order.
+var Directions = [4]Direction{North, East, South, West}
+
 // Maze is a 2D, walled maze. Each cell has four walls around it which can be
 // opened to create a maze.
 type Maze struct {
-	cells [][]cell
+	cells    [][]cell
+	observer Observer
+}
+
+// Observer is notified of every change made to a Maze, e.g. to record or
+// animate it. Calls for walls on the border are not reported, since they have
+// no effect.
+type Observer interface {
+	Opened(row, col int, d Direction)
+	Closed(row, col int, d Direction)
+	Set(row, col int, val string)
 }
 
+// ViewObserver is an Observer that wants to tell changes made in different Sub
+// views apart, for example to keep separate state for views that are modified
+// concurrently. Sub calls View with the view's bounds, and the view reports its
+// changes to the returned Observer, still in m's coordinates.
+type ViewObserver interface {
+	Observer
+	View(row, col, rows, cols int) Observer
+}
+
+// Observe sets the maze's observer, replacing any previous one. Pass nil to
+// stop observing.
+func (m *Maze) Observe(o Observer) { m.observer = o }
+
+// Observer returns the maze's observer, if any.
+func (m *Maze) Observer() Observer { return m.observer }
+
+// NewMaze returns a maze with every wall closed.
 func NewMaze(rows, cols int) *Maze {
 	m := &Maze{
 		cells: make([][]cell, rows),
@@ -29,31 +96,109 @@ func NewMaze(rows, cols int) *Maze {
 	return m
 }
 
+// Sub returns a view of part of the maze, with the given top-left corner and
+// size. The view shares cells with m, so walls opened in the view are opened
+// in m too, but the view cannot open walls on its own borders. Views that do
+// not overlap can be modified concurrently, as long as m's observer is safe
+// for concurrent use. The observer sees changes in m's coordinates.
+func (m *Maze) Sub(row, col, rows, cols int) *Maze {
+	sub := &Maze{cells: make([][]cell, rows)}
+	for r := range sub.cells {
+		sub.cells[r] = m.cells[row+r][col : col+cols]
+	}
+	if m.observer != nil {
+		o := m.observer
+		if v, ok := o.(ViewObserver); ok {
+			o = v.View(row, col, rows, cols)
+		}
+		sub.observer = offsetObserver{o, row, col}
+	}
+	return sub
+}
+
+// offsetObserver translates changes in a Sub view to its parent's coordinates.
+type offsetObserver struct {
+	o        Observer
+	row, col int
+}
+
+func (o offsetObserver) Opened(row, col int, d Direction) { o.o.Opened(row+o.row, col+o.col, d) }
+func (o offsetObserver) Closed(row, col int, d Direction) { o.o.Closed(row+o.row, col+o.col, d) }
+func (o offsetObserver) Set(row, col int, val string)     { o.o.Set(row+o.row, col+o.col, val) }
+
+// Offset returns the parent's observer and where the view starts in the
+// parent, so that other packages can translate their own notifications.
+func (o offsetObserver) Offset() (parent Observer, row, col int) { return o.o, o.row, o.col }
+
+// Open removes the wall on side d of the given cell, along with the matching
+// wall of its neighbor.
 func (m *Maze) Open(row, col int, d Direction) {
-	nextRow, nextCol := row, col
-	var nextDirection Direction
+	nextRow, nextCol, ok := m.Neighbor(row, col, d)
+	if !m.valid(row, col) || !ok {
+		return
+		// TODO: error here?
+	}
+	m.cells[row][col].openings |= d
+	m.cells[nextRow][nextCol].openings |= d.Opposite()
+	if m.observer != nil {
+		m.observer.Opened(row, col, d)
+	}
+}
+
+// Close restores the wall on side d of the given cell, along with the matching
+// wall of its neighbor. Border walls are always closed.
+func (m *Maze) Close(row, col int, d Direction) {
+	nextRow, nextCol, ok := m.Neighbor(row, col, d)
+	if !m.valid(row, col) || !ok {
+		return
+	}
+	m.cells[row][col].openings &= ^d
+	m.cells[nextRow][nextCol].openings &= ^d.Opposite()
+	if m.observer != nil {
+		m.observer.Closed(row, col, d)
+	}
+}
+
+// IsOpen reports whether the wall on side d of the given cell is open. Border
+// walls and out-of-range cells are always closed.
+func (m *Maze) IsOpen(row, col int, d Direction) bool {
+	if !m.valid(row, col) {
+		return false
+	}
+	return m.cells[row][col].openings&d > 0
+}
+
+// Neighbor returns the position of the cell on side d of the given cell, and
+// whether that position is inside the maze.
+func (m *Maze) Neighbor(row, col int, d Direction) (int, int, bool) {
 	switch d {
 	case North:
-		nextRow--
-		nextDirection = South
+		row--
 	case East:
-		nextCol++
-		nextDirection = West
+		col++
 	case South:
-		nextRow++
-		nextDirection = North
+		row++
 	case West:
-		nextCol--
-		nextDirection = East
+		col--
 	default:
 		panic("one direction at a time, please")
 	}
-	if !m.valid(row, col) || !m.valid(nextRow, nextCol) {
-		return
-		// TODO: error here?
+	return row, col, m.valid(row, col)
+}
+
+// Opposite returns the direction facing the other way.
+func (d Direction) Opposite() Direction {
+	switch d {
+	case North:
+		return South
+	case East:
+		return West
+	case South:
+		return North
+	case West:
+		return East
 	}
-	m.cells[row][col].openings |= d
-	m.cells[nextRow][nextCol].openings |= nextDirection
+	panic("one direction at a time, please")
 }
 
 // Set sets a one-rune value to print in the cell.
@@ -62,6 +207,9 @@ func (m *Maze) Set(row, col int, val string) {
 		return
 	}
 	m.cells[row][col].value = val
+	if m.observer != nil {
+		m.observer.Set(row, col, val)
+	}
 }
 
 func (m *Maze) valid(row, col int) bool {
@@ -114,8 +262,16 @@ func (m *Maze) String() string {
 	return b.String()
 }
 
-func (m *Maze) rows() int { return len(m.cells) }
-func (m *Maze) cols() int { return len(m.cells[0]) }
+// Rows returns the height of the maze.
+func (m *Maze) Rows() int { return len(m.cells) }
+
+// Cols returns the width of the maze. A maze with no rows has no columns.
+func (m *Maze) Cols() int {
+	if len(m.cells) == 0 {
+		return 0
+	}
+	return len(m.cells[0])
+}
 
 func (m *Maze) cornerSegmentNW(row, col int) string {
 	mask := North | East | South | West
@@ -126,14 +282,14 @@ func (m *Maze) cornerSegmentNW(row, col int) string {
 	if col == 0 {
 		mask &= ^West
 	}
-	if row >= m.rows() {
+	if row >= m.Rows() {
 		mask &= ^South
 	}
-	if col >= m.cols() {
+	if col >= m.Cols() {
 		mask &= ^East
 	}
 
-	if row < m.rows() && col < m.cols() {
+	if row < m.Rows() && col < m.Cols() {
 		open := m.cells[row][col].openings
 		if open&North > 0 {
 			mask &= ^East
@@ -142,10 +298,10 @@ func (m *Maze) cornerSegmentNW(row, col int) string {
 			mask &= ^South
 		}
 	}
-	if row-1 >= 0 && col < m.cols() && m.cells[row-1][col].openings&West > 0 {
+	if row-1 >= 0 && col < m.Cols() && m.cells[row-1][col].openings&West > 0 {
 		mask &= ^North
 	}
-	if col-1 >= 0 && row < m.rows() && m.cells[row][col-1].openings&North > 0 {
+	if col-1 >= 0 && row < m.Rows() && m.cells[row][col-1].openings&North > 0 {
 		mask &= ^West
 	}
 	return segment(mask)
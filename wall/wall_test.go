@@ -17,3 +17,59 @@ func TestWall(t *testing.T) {
 	// TODO: more thorough testing.
 	t.Logf("Maze:\n%v\n", m)
 }
+
+func TestIsOpen(t *testing.T) {
+	m := wall.NewMaze(3, 3)
+	m.Open(1, 1, wall.North)
+	m.Open(1, 1, wall.East)
+	m.Open(0, 0, wall.West) // border, ignored
+	tests := []struct {
+		row, col int
+		d        wall.Direction
+		want     bool
+	}{
+		{1, 1, wall.North, true},
+		{0, 1, wall.South, true},
+		{1, 1, wall.East, true},
+		{1, 2, wall.West, true},
+		{1, 1, wall.South, false},
+		{1, 1, wall.West, false},
+		{0, 0, wall.West, false},
+		{-1, 0, wall.South, false},
+	}
+	for _, tt := range tests {
+		if got := m.IsOpen(tt.row, tt.col, tt.d); got != tt.want {
+			t.Errorf("IsOpen(%d, %d, %v) = %v, want %v", tt.row, tt.col, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	m := wall.NewMaze(2, 2)
+	m.Open(0, 0, wall.East)
+	m.Open(0, 0, wall.South)
+	m.Close(0, 1, wall.West)
+	if m.IsOpen(0, 0, wall.East) || m.IsOpen(0, 1, wall.West) {
+		t.Errorf("East wall still open:\n%v", m)
+	}
+	if !m.IsOpen(0, 0, wall.South) || !m.IsOpen(1, 0, wall.North) {
+		t.Errorf("South wall should not be affected:\n%v", m)
+	}
+}
+
+func TestSub(t *testing.T) {
+	m := wall.NewMaze(4, 4)
+	sub := m.Sub(1, 2, 2, 2)
+	sub.Open(0, 0, wall.East)
+	sub.Open(1, 1, wall.East) // sub border, ignored
+	sub.Open(0, 0, wall.North)
+	if sub.Rows() != 2 || sub.Cols() != 2 {
+		t.Errorf("Sub is %dx%d, want 2x2", sub.Rows(), sub.Cols())
+	}
+	if !m.IsOpen(1, 2, wall.East) || !m.IsOpen(1, 3, wall.West) {
+		t.Errorf("Opening in sub not reflected in maze:\n%v", m)
+	}
+	if m.IsOpen(2, 3, wall.East) || m.IsOpen(1, 2, wall.North) {
+		t.Errorf("Sub opened its own border:\n%v", m)
+	}
+}
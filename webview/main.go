@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/misterikkit/automata/gen"
+	"github.com/misterikkit/automata/maze/game"
+	"github.com/misterikkit/automata/maze/gene"
+	"github.com/misterikkit/automata/trace"
+	"github.com/misterikkit/automata/wall"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to serve on")
+	flag.Parse()
+
+	http.HandleFunc("/", serveIndex)
+	http.HandleFunc("/automaton", serveAutomaton)
+	http.HandleFunc("/maze", serveMaze)
+	log.Printf("Serving on http://%s/", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+func serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if err := page.Execute(w, gen.Names()); err != nil {
+		log.Printf("Page render fail: %v", err)
+	}
+}
+
+// automatonFrame is one generation of a game, sent as a Server-Sent Event.
+type automatonFrame struct {
+	Gen   int    `json:"gen"`
+	Gene  string `json:"gene"`
+	Seed  int64  `json:"seed"`
+	Rows  int    `json:"rows"`
+	Cols  int    `json:"cols"`
+	Cells string `json:"cells"` // row-major, '1' for alive
+}
+
+// serveAutomaton streams the generations of a cellular automaton until the
+// page goes away or the generation limit is reached.
+func serveAutomaton(w http.ResponseWriter, r *http.Request) {
+	q := params{r, nil}
+	h, wd := q.size("h", 40), q.size("w", 100)
+	seed := q.int64("seed", time.Now().UnixNano())
+	density := q.float("density", 0.01)
+	gens := q.between("gens", 500, 0, maxGens)
+	delay := time.Duration(q.int("speed", 100)) * time.Millisecond
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Use our own rng rather than the global one, since requests run
+	// concurrently.
+	rng := rand.New(rand.NewSource(seed))
+	geneStr := r.FormValue("gene")
+	if len(geneStr) == 0 {
+		bits := make([]string, 18)
+		for i := range bits {
+			bits[i] = strconv.Itoa(rng.Intn(2))
+		}
+		geneStr = strings.Join(bits, "")
+	}
+	if len(geneStr) != 18 || strings.Trim(geneStr, "01") != "" {
+		http.Error(w, fmt.Sprintf("gene %q should be 18 binary digits", geneStr), http.StatusBadRequest)
+		return
+	}
+	gn := gene.FromString(geneStr)
+	rule := gn.AsRule()
+	g := game.New(h, wd).Next(func(game.Game, int, int) game.Cell { return rng.Float64() < density })
+
+	send, ok := eventStream(w)
+	if !ok {
+		return
+	}
+	next, stop := pace(r.Context(), delay)
+	defer stop()
+	for i := 0; i <= gens; i++ {
+		frame := automatonFrame{Gen: i, Gene: gn.String(), Seed: seed, Rows: h, Cols: wd}
+		var b strings.Builder
+		for _, row := range g {
+			for _, alive := range row {
+				if alive {
+					b.WriteByte('1')
+				} else {
+					b.WriteByte('0')
+				}
+			}
+		}
+		frame.Cells = b.String()
+		if err := send("frame", frame); err != nil {
+			return
+		}
+		g = g.Next(rule)
+		if !next() {
+			return
+		}
+	}
+	send("done", nil)
+}
+
+// serveMaze generates a maze, then streams its trace one step at a time.
+func serveMaze(w http.ResponseWriter, r *http.Request) {
+	q := params{r, nil}
+	h, wd := q.size("h", 20), q.size("w", 40)
+	seed := q.int64("seed", time.Now().UnixNano())
+	delay := time.Duration(q.int("speed", 20)) * time.Millisecond
+	if q.err != nil {
+		http.Error(w, q.err.Error(), http.StatusBadRequest)
+		return
+	}
+	g, ok := gen.Lookup(r.FormValue("algo"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown algorithm %q", r.FormValue("algo")), http.StatusBadRequest)
+		return
+	}
+	maze := wall.NewMaze(h, wd)
+	tr := trace.Record(maze, true)
+	g(maze, rand.New(rand.NewSource(seed)))
+
+	send, ok := eventStream(w)
+	if !ok {
+		return
+	}
+	if err := send("init", struct {
+		*trace.Trace
+		Seed  int64 `json:"seed"`
+		Steps int   `json:"steps"`
+	}{tr, seed, len(tr.Steps)}); err != nil {
+		return
+	}
+	next, stop := pace(r.Context(), delay)
+	defer stop()
+	for _, s := range tr.Steps {
+		if err := send("step", s); err != nil {
+			return
+		}
+		if !next() {
+			return
+		}
+	}
+	send("done", nil)
+}
+
+// pace returns a function that waits for the next frame, and reports false if
+// the page went away. A delay of zero or less sends frames as fast as possible.
+func pace(ctx context.Context, delay time.Duration) (next func() bool, stop func()) {
+	if delay <= 0 {
+		return func() bool { return ctx.Err() == nil }, func() {}
+	}
+	tick := time.NewTicker(delay)
+	return func() bool {
+		select {
+		case <-tick.C:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, tick.Stop
+}
+
+// eventStream starts a Server-Sent Events response, and returns a function to
+// send JSON-encoded events on it.
+func eventStream(w http.ResponseWriter) (func(event string, v interface{}) error, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return nil, false
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	return func(event string, v interface{}) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}, true
+}
+
+// params parses query parameters, keeping the first error.
+type params struct {
+	r   *http.Request
+	err error
+}
+
+// Limits on a request, so that one page can't use up all the server's memory
+// or keep a stream open forever.
+const (
+	maxSize = 500
+	maxGens = 10000
+)
+
+// size parses a number of rows or columns.
+func (p *params) size(name string, def int) int { return p.between(name, def, 1, maxSize) }
+
+// between parses an int that must be between lo and hi, inclusive.
+func (p *params) between(name string, def, lo, hi int) int {
+	v := p.int(name, def)
+	if p.err == nil && (v < lo || v > hi) {
+		p.err = fmt.Errorf("bad %s: %d is not between %d and %d", name, v, lo, hi)
+	}
+	return v
+}
+
+func (p *params) int(name string, def int) int { return int(p.int64(name, int64(def))) }
+
+func (p *params) int64(name string, def int64) int64 {
+	s := p.r.FormValue(name)
+	if len(s) == 0 || p.err != nil {
+		return def
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		p.err = fmt.Errorf("bad %s: %v", name, err)
+	}
+	return v
+}
+
+func (p *params) float(name string, def float64) float64 {
+	s := p.r.FormValue(name)
+	if len(s) == 0 || p.err != nil {
+		return def
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		p.err = fmt.Errorf("bad %s: %v", name, err)
+	}
+	return v
+}
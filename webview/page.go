@@ -0,0 +1,159 @@
+package main
+
+import "html/template"
+
+// page is the viewer, given the registered maze algorithms. It is inline so
+// the command is a single binary with nothing to install.
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>automata</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+fieldset { display: inline-block; vertical-align: top; }
+label { display: block; margin: 0.2em 0; }
+canvas { display: block; margin-top: 1em; border: 1px solid #ccc; }
+#status { font-family: monospace; }
+</style>
+</head>
+<body>
+<fieldset>
+  <legend>Mode</legend>
+  <label><input type="radio" name="mode" value="automaton" checked> Automaton</label>
+  <label><input type="radio" name="mode" value="maze"> Maze generator</label>
+</fieldset>
+<fieldset id="automaton">
+  <legend>Automaton</legend>
+  <label>Gene <input id="gene" size="20" placeholder="random"></label>
+  <label>Density <input id="density" type="number" value="0.01" min="0" max="1" step="0.01"></label>
+</fieldset>
+<fieldset id="maze" hidden>
+  <legend>Maze</legend>
+  <label>Algorithm <select id="algo">{{range .}}<option>{{.}}</option>{{end}}</select></label>
+</fieldset>
+<fieldset>
+  <legend>Run</legend>
+  <label>Seed <input id="seed" type="number" placeholder="random"></label>
+  <label>Delay (ms) <input id="speed" type="number" value="50" min="0"></label>
+  <button id="start">Start</button> <button id="stop">Stop</button>
+</fieldset>
+<div id="status"></div>
+<canvas id="canvas"></canvas>
+<script>
+const $ = id => document.getElementById(id);
+const canvas = $("canvas"), ctx = canvas.getContext("2d");
+let source = null;
+
+function mode() { return document.querySelector("input[name=mode]:checked").value; }
+document.querySelectorAll("input[name=mode]").forEach(r => r.onchange = () => {
+  $("automaton").hidden = mode() !== "automaton";
+  $("maze").hidden = mode() !== "maze";
+});
+
+function stop() { if (source) { source.close(); source = null; } }
+$("stop").onclick = stop;
+
+$("start").onclick = () => {
+  stop();
+  const q = new URLSearchParams({speed: $("speed").value});
+  if ($("seed").value) q.set("seed", $("seed").value);
+  if (mode() === "automaton") {
+    if ($("gene").value) q.set("gene", $("gene").value);
+    q.set("density", $("density").value);
+    source = new EventSource("/automaton?" + q);
+    source.addEventListener("frame", e => drawGame(JSON.parse(e.data)));
+  } else {
+    q.set("algo", $("algo").value);
+    source = new EventSource("/maze?" + q);
+    source.addEventListener("init", e => initMaze(JSON.parse(e.data)));
+    source.addEventListener("step", e => stepMaze(JSON.parse(e.data)));
+  }
+  source.addEventListener("done", stop);
+  source.onerror = () => { $("status").textContent += " (stream closed)"; stop(); };
+};
+
+function drawGame(f) {
+  const size = 6;
+  canvas.width = f.cols * size;
+  canvas.height = f.rows * size;
+  ctx.fillStyle = "#fff";
+  ctx.fillRect(0, 0, canvas.width, canvas.height);
+  ctx.fillStyle = "#000";
+  for (let i = 0; i < f.cells.length; i++) {
+    if (f.cells[i] === "1") {
+      ctx.fillRect((i % f.cols) * size, Math.floor(i / f.cols) * size, size, size);
+    }
+  }
+  $("status").textContent = "gene " + f.gene + " seed " + f.seed + " generation " + f.gen;
+}
+
+// Maze state mirrors wall.Maze: a bit per open direction, plus a value.
+const dirs = {N: [1, -1, 0], E: [2, 0, 1], S: [4, 1, 0], W: [8, 0, -1]};
+const opposite = {N: "S", E: "W", S: "N", W: "E"};
+let maze = null;
+
+function initMaze(t) {
+  maze = {rows: t.rows, cols: t.cols, seed: t.seed, steps: t.steps, size: 12,
+    open: new Uint8Array(t.rows * t.cols), val: new Array(t.rows * t.cols).fill(""),
+    current: null, frontier: []};
+  canvas.width = t.cols * maze.size + 1;
+  canvas.height = t.rows * maze.size + 1;
+  drawMaze(0);
+}
+
+function setWall(row, col, dir, open) {
+  for (const d of dir) {
+    const [bit, dr, dc] = dirs[d];
+    const r = row + dr, c = col + dc;
+    mark(row, col, bit, open);
+    if (r >= 0 && r < maze.rows && c >= 0 && c < maze.cols) mark(r, c, dirs[opposite[d]][0], open);
+  }
+}
+
+function mark(row, col, bit, open) {
+  const i = row * maze.cols + col;
+  maze.open[i] = open ? maze.open[i] | bit : maze.open[i] & ~bit;
+}
+
+function stepMaze(s) {
+  if (!maze) return;
+  if (s.op === "open") setWall(s.row, s.col, s.dir, true);
+  if (s.op === "close") setWall(s.row, s.col, s.dir, false);
+  if (s.op === "set") maze.val[s.row * maze.cols + s.col] = s.val || "";
+  // Steps with focus always have a current cell, but omit an empty frontier.
+  if (s.current) {
+    maze.current = s.current;
+    maze.frontier = s.frontier || [];
+  }
+  drawMaze(s.step);
+}
+
+function drawMaze(n) {
+  const z = maze.size;
+  ctx.fillStyle = "#fff";
+  ctx.fillRect(0, 0, canvas.width, canvas.height);
+  ctx.fillStyle = "#acf";
+  for (const c of maze.frontier) ctx.fillRect(c.col * z, c.row * z, z, z);
+  ctx.fillStyle = "#f66";
+  if (maze.current) ctx.fillRect(maze.current.col * z, maze.current.row * z, z, z);
+  ctx.fillStyle = "#000";
+  ctx.font = (z - 2) + "px monospace";
+  ctx.beginPath();
+  for (let r = 0; r < maze.rows; r++) {
+    for (let c = 0; c < maze.cols; c++) {
+      const i = r * maze.cols + c, x = c * z + 0.5, y = r * z + 0.5;
+      if (!(maze.open[i] & 1)) { ctx.moveTo(x, y); ctx.lineTo(x + z, y); }
+      if (!(maze.open[i] & 8)) { ctx.moveTo(x, y); ctx.lineTo(x, y + z); }
+      if (r === maze.rows - 1 && !(maze.open[i] & 4)) { ctx.moveTo(x, y + z); ctx.lineTo(x + z, y + z); }
+      if (c === maze.cols - 1 && !(maze.open[i] & 2)) { ctx.moveTo(x + z, y); ctx.lineTo(x + z, y + z); }
+      if (maze.val[i]) ctx.fillText(maze.val[i], x + 2, y + z - 2);
+    }
+  }
+  ctx.stroke();
+  $("status").textContent = "seed " + maze.seed + " step " + n + " / " + maze.steps;
+}
+</script>
+</body>
+</html>
+`))